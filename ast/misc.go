@@ -461,6 +461,8 @@ type AdminStmtType int
 const (
 	AdminShowDDL = iota + 1
 	AdminCheckTable
+	AdminShowAnalyzeJobs
+	AdminCancelAnalyzeJobs
 )
 
 // AdminStmt is the struct for Admin statement.
@@ -469,6 +471,7 @@ type AdminStmt struct {
 
 	Tp     AdminStmtType
 	Tables []*TableName
+	JobIDs []int64
 }
 
 // Accept implements Node Accpet interface.
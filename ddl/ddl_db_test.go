@@ -453,7 +453,8 @@ LOOP:
 	}
 	// Make sure there is no index with name c3_index.
 	c.Assert(nidx, IsNil)
-	idx := tables.NewIndex(t.Meta(), c3idx.Meta())
+	idx, err := tables.NewIndex(t.Meta(), c3idx.Meta())
+	c.Assert(err, IsNil)
 	c.Assert(ctx.NewTxn(), IsNil)
 	defer ctx.Txn().Rollback()
 
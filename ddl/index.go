@@ -494,9 +494,13 @@ func (d *ddl) addTableIndex(t table.Table, indexInfo *model.IndexInfo, reorgInfo
 		col := cols[v.Offset]
 		colMap[col.ID] = &col.FieldType
 	}
+	tblIndex, err := tables.NewIndex(t.Meta(), indexInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	taskCnt := defaultTaskCnt
 	taskOpInfo := &indexTaskOpInfo{
-		tblIndex:  tables.NewIndex(t.Meta(), indexInfo),
+		tblIndex:  tblIndex,
 		colMap:    colMap,
 		nextCh:    make(chan int64, 1),
 		taskRetCh: make(chan *taskResult, taskCnt),
@@ -616,7 +616,8 @@ func (s *testIndexSuite) TestDropIndex(c *C) {
 	testCheckJobDone(c, d, job, true)
 
 	checkOK := false
-	oldIndexCol := tables.NewIndex(tblInfo, &model.IndexInfo{})
+	oldIndexCol, err := tables.NewIndex(tblInfo, &model.IndexInfo{})
+	c.Assert(err, IsNil)
 
 	tc := &testDDLCallback{}
 	tc.onJobUpdated = func(job *model.Job) {
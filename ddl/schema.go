@@ -111,6 +111,11 @@ func (d *ddl) onDropSchema(t *meta.Meta, job *model.Job) error {
 		if err = t.DropDatabase(dbInfo.ID); err != nil {
 			break
 		}
+		for _, tblInfo := range tables {
+			if err = delAnalyzeCheckpoint(t, tblInfo.ID); err != nil {
+				return errors.Trace(err)
+			}
+		}
 
 		// Finish this job.
 		job.BinlogInfo.AddDBInfo(ver, dbInfo)
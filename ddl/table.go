@@ -105,6 +105,9 @@ func (d *ddl) onDropTable(t *meta.Meta, job *model.Job) error {
 		if err = t.DropTable(job.SchemaID, job.TableID); err != nil {
 			break
 		}
+		if err = delAnalyzeCheckpoint(t, job.TableID); err != nil {
+			break
+		}
 		// Finish this job.
 		job.State = model.JobDone
 		job.SchemaState = model.StateNone
@@ -177,6 +180,28 @@ func (d *ddl) dropTableData(startKey kv.Key, job *model.Job, limit int) (int, er
 	return delCount, errors.Trace(err)
 }
 
+// delAnalyzeCheckpoint deletes the analyze checkpoint of a dropped table and its sampled rows.
+// A job that is still analyzing the table is cancelled, so it doesn't save the checkpoint again.
+func delAnalyzeCheckpoint(t *meta.Meta, tableID int64) error {
+	cp, err := t.GetAnalyzeCheckpoint(tableID)
+	if err != nil || cp == nil {
+		return errors.Trace(err)
+	}
+	job, err := t.GetAnalyzeJob(cp.JobID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if job != nil && job.IsRunning() {
+		job.State = model.AnalyzeJobCancelled
+		job.Processing = ""
+		err = t.SetAnalyzeJob(job)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(t.DelAnalyzeCheckpoint(tableID))
+}
+
 // onTruncateTable delete old table meta, and creates a new table identical to old table except for table ID.
 // As all the old data is encoded with old table ID, it can not be accessed any more.
 // A background job will be created to delete old data.
@@ -199,6 +224,10 @@ func (d *ddl) onTruncateTable(t *meta.Meta, job *model.Job) error {
 		job.State = model.JobCancelled
 		return errors.Trace(err)
 	}
+	err = delAnalyzeCheckpoint(t, tableID)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	tblInfo.ID = newTableID
 	err = t.CreateTable(schemaID, tblInfo)
 	if err != nil {
@@ -0,0 +1,518 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/plan/statistics"
+	"github.com/pingcap/tidb/plan/statscache"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/types"
+)
+
+var (
+	// analyzeSaveInterval is the interval a running analyze job saves its progress,
+	// it is also the interval the job checks whether it is cancelled.
+	analyzeSaveInterval = time.Second
+	// analyzeJobLease is the time after which a running analyze job that doesn't save its progress
+	// is regarded as interrupted, e.g. its server is down. The next analyze job on the table takes over
+	// its checkpoint, and cancelling the job doesn't need to wait for it.
+	analyzeJobLease = time.Minute
+	// analyzeCheckpointMaxAge is the max age of a checkpoint that the next analyze job resumes from.
+	// The rows scanned before an older checkpoint may have changed too much since then, so the
+	// checkpoint is dropped and the table is sampled from the start.
+	analyzeCheckpointMaxAge = 10 * time.Minute
+)
+
+// maxAnalyzeJobHistory is the max number of done analyze jobs kept for "admin show analyze jobs".
+const maxAnalyzeJobHistory = 32
+
+// analyzeJob is an analyze job running on this server.
+// The job and its sampling progress are saved in meta, so every server can show and cancel it,
+// and the next analyze job on the table resumes sampling from the checkpoint if it is interrupted.
+// Statistics are only saved after the whole table is analyzed, so an interrupted job never leaves
+// partially built statistics behind.
+type analyzeJob struct {
+	store kv.Storage
+	tbl   table.Table
+	cols  []*table.Column
+	job   *model.AnalyzeJob
+	cp    *model.AnalyzeCheckpoint
+	// samples are the sampled rows, dirty is the indices of samples changed since the last save.
+	samples  [][]types.Datum
+	dirty    map[int]struct{}
+	lastSave time.Time
+}
+
+func isAnalyzeJobExpired(job *model.AnalyzeJob) bool {
+	return time.Now().UnixNano()-job.LastUpdateTS > int64(analyzeJobLease)
+}
+
+func isAnalyzeCheckpointExpired(cp *model.AnalyzeCheckpoint) bool {
+	return time.Now().UnixNano()-cp.LastUpdateTS > int64(analyzeCheckpointMaxAge)
+}
+
+func analyzeColumnIDs(cols []*table.Column) []int64 {
+	ids := make([]int64, 0, len(cols))
+	for _, col := range cols {
+		ids = append(ids, col.ID)
+	}
+	return ids
+}
+
+func equalColumnIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// startAnalyzeJob adds a running analyze job for the table, only one job can analyze a table at a time.
+func startAnalyzeJob(store kv.Storage, connID uint64, dbName string, tbl table.Table) (*analyzeJob, error) {
+	aj := &analyzeJob{
+		store:    store,
+		tbl:      tbl,
+		cols:     tbl.Cols(),
+		dirty:    make(map[int]struct{}),
+		lastSave: time.Now(),
+	}
+	tblInfo := tbl.Meta()
+	colIDs := analyzeColumnIDs(aj.cols)
+	err := kv.RunInNewTxn(store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		cp, err := m.GetAnalyzeCheckpoint(tblInfo.ID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var samples [][]types.Datum
+		if cp != nil {
+			old, err := m.GetAnalyzeJob(cp.JobID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if old != nil && old.IsRunning() {
+				if !isAnalyzeJobExpired(old) {
+					return ErrAnalyzeInProgress.Gen("table %s.%s is being analyzed by job %d", dbName, tblInfo.Name.O, old.ID)
+				}
+				old.State = model.AnalyzeJobFailed
+				old.Processing = ""
+				old.Error = "interrupted"
+				err = m.SetAnalyzeJob(old)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			if cp.Count > 0 && equalColumnIDs(cp.ColumnIDs, colIDs) && !isAnalyzeCheckpointExpired(cp) {
+				samples, err = aj.loadSamples(m)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			} else {
+				err = m.DelAnalyzeCheckpoint(tblInfo.ID)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				cp = nil
+			}
+		}
+
+		id, err := m.GenGlobalID()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		now := time.Now().UnixNano()
+		job := &model.AnalyzeJob{
+			ID:           id,
+			ConnID:       connID,
+			SchemaName:   dbName,
+			TableName:    tblInfo.Name.O,
+			TableID:      tblInfo.ID,
+			State:        model.AnalyzeJobRunning,
+			StartTS:      now,
+			LastUpdateTS: now,
+		}
+		if cp == nil {
+			cp = &model.AnalyzeCheckpoint{ColumnIDs: colIDs, LastUpdateTS: now}
+		} else {
+			job.ResumedFrom = cp.JobID
+			job.RowCount = cp.Count
+		}
+		// The checkpoint is written even if it is not changed, so concurrent jobs
+		// on the same table conflict with each other.
+		cp.JobID = id
+		err = m.SetAnalyzeCheckpoint(tblInfo.ID, cp)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = m.SetAnalyzeJob(job)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = trimAnalyzeJobs(m)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		aj.job, aj.cp, aj.samples = job, cp, samples
+		return nil
+	})
+	return aj, errors.Trace(err)
+}
+
+// trimAnalyzeJobs deletes the oldest done jobs if there are too many of them.
+func trimAnalyzeJobs(m *meta.Meta) error {
+	jobs, err := m.GetAllAnalyzeJobs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	doneCount := 0
+	for _, job := range jobs {
+		if !job.IsRunning() {
+			doneCount++
+		}
+	}
+	for _, job := range jobs {
+		if doneCount <= maxAnalyzeJobHistory {
+			break
+		}
+		if job.IsRunning() {
+			continue
+		}
+		err = m.DelAnalyzeJob(job.ID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		doneCount--
+	}
+	return nil
+}
+
+func (aj *analyzeJob) loadSamples(m *meta.Meta) ([][]types.Datum, error) {
+	rows, err := m.GetAnalyzeSamples(aj.tbl.Meta().ID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	colTps := make(map[int64]*types.FieldType, len(aj.cols))
+	for _, col := range aj.cols {
+		colTps[col.ID] = &col.FieldType
+	}
+	samples := make([][]types.Datum, 0, len(rows))
+	for _, b := range rows {
+		rowMap, err := tablecodec.DecodeRow(b, colTps)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		row := make([]types.Datum, len(aj.cols))
+		for i, col := range aj.cols {
+			row[i] = rowMap[col.ID]
+		}
+		samples = append(samples, row)
+	}
+	return samples, nil
+}
+
+// collectSamples samples the rows after the checkpoint, using Reservoir Sampling algorithm.
+// See https://en.wikipedia.org/wiki/Reservoir_sampling
+// The rows scanned before the checkpoint are read by an earlier transaction, so the
+// statistics of a resumed job may miss the changes made to them since then. This is why
+// a checkpoint older than analyzeCheckpointMaxAge is never resumed.
+func (aj *analyzeJob) collectSamples(ctx context.Context) error {
+	startKey := aj.tbl.FirstKey()
+	if aj.cp.Count > 0 {
+		if aj.cp.Handle == math.MaxInt64 {
+			return nil
+		}
+		startKey = aj.tbl.RecordKey(aj.cp.Handle + 1)
+	}
+	err := aj.tbl.IterRecords(ctx, startKey, aj.cols, func(h int64, row []types.Datum, cols []*table.Column) (bool, error) {
+		aj.addSample(row)
+		aj.cp.Handle = h
+		aj.cp.Count++
+		aj.job.RowCount = aj.cp.Count
+		if time.Since(aj.lastSave) < analyzeSaveInterval {
+			return true, nil
+		}
+		return true, errors.Trace(aj.saveProgress(true))
+	})
+	return errors.Trace(err)
+}
+
+func (aj *analyzeJob) addSample(row []types.Datum) {
+	if len(aj.samples) < maxSampleCount {
+		aj.samples = append(aj.samples, row)
+		aj.dirty[len(aj.samples)-1] = struct{}{}
+		return
+	}
+	shouldAdd := rand.Int63n(aj.cp.Count) < maxSampleCount
+	if shouldAdd {
+		idx := rand.Intn(maxSampleCount)
+		aj.samples[idx] = row
+		aj.dirty[idx] = struct{}{}
+	}
+}
+
+// setProcessing records the column or index that is being built, it is the Progress of statistics.Builder.
+func (aj *analyzeJob) setProcessing(name string) error {
+	aj.job.Processing = name
+	return errors.Trace(aj.saveProgress(false))
+}
+
+// checkJob returns ErrAnalyzeCancelled if the job is cancelled or is no longer run by this server.
+func (aj *analyzeJob) checkJob(m *meta.Meta) error {
+	job, err := m.GetAnalyzeJob(aj.job.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if job == nil || !job.IsRunning() || job.Cancelling {
+		return ErrAnalyzeCancelled.Gen("analyze job %d is cancelled", aj.job.ID)
+	}
+	return nil
+}
+
+// saveProgress saves the job and the checkpoint if withCheckpoint is true.
+// It returns ErrAnalyzeCancelled if the job is cancelled.
+func (aj *analyzeJob) saveProgress(withCheckpoint bool) error {
+	err := kv.RunInNewTxn(aj.store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		err := aj.checkJob(m)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		aj.job.LastUpdateTS = time.Now().UnixNano()
+		err = m.SetAnalyzeJob(aj.job)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !withCheckpoint {
+			return nil
+		}
+		return errors.Trace(aj.saveCheckpoint(m))
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if withCheckpoint {
+		aj.dirty = make(map[int]struct{})
+	}
+	aj.lastSave = time.Now()
+	return nil
+}
+
+func (aj *analyzeJob) saveCheckpoint(m *meta.Meta) error {
+	tableID := aj.tbl.Meta().ID
+	for idx := range aj.dirty {
+		b, err := tablecodec.EncodeRow(aj.samples[idx], aj.cp.ColumnIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = m.SetAnalyzeSample(tableID, int64(idx), b)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	aj.cp.SampleCount = int64(len(aj.samples))
+	aj.cp.LastUpdateTS = time.Now().UnixNano()
+	return errors.Trace(m.SetAnalyzeCheckpoint(tableID, aj.cp))
+}
+
+// finish saves the statistics and marks the job as done in one transaction.
+func (aj *analyzeJob) finish(t *statistics.Table) error {
+	tpb, err := t.ToPB()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = kv.RunInNewTxn(aj.store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		err := aj.checkJob(m)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = m.SetTableStats(aj.job.TableID, tpb)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = m.DelAnalyzeCheckpoint(aj.job.TableID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		aj.job.State = model.AnalyzeJobDone
+		aj.job.Processing = ""
+		aj.job.LastUpdateTS = time.Now().UnixNano()
+		return errors.Trace(m.SetAnalyzeJob(aj.job))
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statscache.SetStatisticsTableCache(aj.job.TableID, t)
+	return nil
+}
+
+// abort marks the job as cancelled or failed according to err. A failed job saves its checkpoint
+// if keepProgress is true, so the next job on the table resumes from it, while a cancelled
+// job drops its checkpoint.
+func (aj *analyzeJob) abort(err error, keepProgress bool) error {
+	return kv.RunInNewTxn(aj.store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		job, err1 := m.GetAnalyzeJob(aj.job.ID)
+		if err1 != nil {
+			return errors.Trace(err1)
+		}
+		if job == nil || !job.IsRunning() {
+			// The job is cancelled or taken over by others.
+			return nil
+		}
+		aj.job.Processing = ""
+		aj.job.LastUpdateTS = time.Now().UnixNano()
+		if job.Cancelling || terror.ErrorEqual(err, ErrAnalyzeCancelled) {
+			aj.job.State = model.AnalyzeJobCancelled
+			err1 = m.DelAnalyzeCheckpoint(aj.job.TableID)
+		} else {
+			aj.job.State = model.AnalyzeJobFailed
+			aj.job.Error = errors.Cause(err).Error()
+			if keepProgress {
+				err1 = aj.saveCheckpoint(m)
+			}
+		}
+		if err1 != nil {
+			return errors.Trace(err1)
+		}
+		return errors.Trace(m.SetAnalyzeJob(aj.job))
+	})
+}
+
+// cancelAnalyzeJob asks the running job to stop, the job stops the next time it saves its progress.
+// If the job is interrupted, it is cancelled at once.
+func cancelAnalyzeJob(store kv.Storage, id int64) error {
+	return kv.RunInNewTxn(store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		job, err := m.GetAnalyzeJob(id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if job == nil || !job.IsRunning() {
+			return ErrAnalyzeJobNotFound.Gen("running analyze job %d not found", id)
+		}
+		if !isAnalyzeJobExpired(job) {
+			job.Cancelling = true
+			return errors.Trace(m.SetAnalyzeJob(job))
+		}
+		job.State = model.AnalyzeJobCancelled
+		job.Processing = ""
+		cp, err := m.GetAnalyzeCheckpoint(job.TableID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if cp != nil && cp.JobID == job.ID {
+			err = m.DelAnalyzeCheckpoint(job.TableID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return errors.Trace(m.SetAnalyzeJob(job))
+	})
+}
+
+func analyzeJobToDatums(job *model.AnalyzeJob) []types.Datum {
+	state := job.State.String()
+	if job.IsRunning() && isAnalyzeJobExpired(job) {
+		// Its server is down or has lost its connection to the store.
+		state = "interrupted"
+	}
+	return types.MakeDatums(
+		job.ID,
+		job.ConnID,
+		job.SchemaName,
+		job.TableName,
+		state,
+		job.RowCount,
+		job.Processing,
+		time.Unix(0, job.StartTS).Format("2006-01-02 15:04:05"),
+		job.Error,
+	)
+}
+
+// ShowAnalyzeJobsExec represents a show analyze jobs executor.
+type ShowAnalyzeJobsExec struct {
+	schema expression.Schema
+	jobs   []*model.AnalyzeJob
+	cursor int
+}
+
+// Schema implements the Executor Schema interface.
+func (e *ShowAnalyzeJobsExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *ShowAnalyzeJobsExec) Next() (*Row, error) {
+	if e.cursor >= len(e.jobs) {
+		return nil, nil
+	}
+	row := &Row{Data: analyzeJobToDatums(e.jobs[e.cursor])}
+	e.cursor++
+	return row, nil
+}
+
+// Close implements the Executor Close interface.
+func (e *ShowAnalyzeJobsExec) Close() error {
+	return nil
+}
+
+// CancelAnalyzeJobsExec represents a cancel analyze jobs executor.
+// It returns a row for each job telling whether it is cancelled successfully.
+type CancelAnalyzeJobsExec struct {
+	schema expression.Schema
+	store  kv.Storage
+	jobIDs []int64
+	cursor int
+}
+
+// Schema implements the Executor Schema interface.
+func (e *CancelAnalyzeJobsExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *CancelAnalyzeJobsExec) Next() (*Row, error) {
+	if e.cursor >= len(e.jobIDs) {
+		return nil, nil
+	}
+	id := e.jobIDs[e.cursor]
+	result := "successful"
+	if err := cancelAnalyzeJob(e.store, id); err != nil {
+		result = errors.Cause(err).Error()
+	}
+	e.cursor++
+	return &Row{Data: types.MakeDatums(id, result)}, nil
+}
+
+// Close implements the Executor Close interface.
+func (e *CancelAnalyzeJobsExec) Close() error {
+	return nil
+}
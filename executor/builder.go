@@ -24,8 +24,10 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/inspectkv"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/plan"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -75,6 +77,10 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 		return b.buildSelectLock(v)
 	case *plan.ShowDDL:
 		return b.buildShowDDL(v)
+	case *plan.ShowAnalyzeJobs:
+		return b.buildShowAnalyzeJobs(v)
+	case *plan.CancelAnalyzeJobs:
+		return b.buildCancelAnalyzeJobs(v)
 	case *plan.Show:
 		return b.buildShow(v)
 	case *plan.Simple:
@@ -148,6 +154,27 @@ func (b *executorBuilder) buildShowDDL(v *plan.ShowDDL) Executor {
 	return e
 }
 
+func (b *executorBuilder) buildShowAnalyzeJobs(v *plan.ShowAnalyzeJobs) Executor {
+	// Like ShowDDL, the jobs must be read before the transaction is committed.
+	jobs, err := meta.NewMeta(b.ctx.Txn()).GetAllAnalyzeJobs()
+	if err != nil {
+		b.err = errors.Trace(err)
+		return nil
+	}
+	return &ShowAnalyzeJobsExec{
+		schema: v.GetSchema(),
+		jobs:   jobs,
+	}
+}
+
+func (b *executorBuilder) buildCancelAnalyzeJobs(v *plan.CancelAnalyzeJobs) Executor {
+	return &CancelAnalyzeJobsExec{
+		schema: v.GetSchema(),
+		store:  sessionctx.GetDomain(b.ctx).Store(),
+		jobIDs: v.JobIDs,
+	}
+}
+
 func (b *executorBuilder) buildCheckTable(v *plan.CheckTable) Executor {
 	return &CheckTableExec{
 		tables: v.Tables,
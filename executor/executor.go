@@ -38,6 +38,7 @@ import (
 
 var (
 	_ Executor = &ApplyExec{}
+	_ Executor = &CancelAnalyzeJobsExec{}
 	_ Executor = &CheckTableExec{}
 	_ Executor = &DistinctExec{}
 	_ Executor = &DummyScanExec{}
@@ -49,6 +50,7 @@ var (
 	_ Executor = &ReverseExec{}
 	_ Executor = &SelectionExec{}
 	_ Executor = &SelectLockExec{}
+	_ Executor = &ShowAnalyzeJobsExec{}
 	_ Executor = &ShowDDLExec{}
 	_ Executor = &SortExec{}
 	_ Executor = &StreamAggExec{}
@@ -69,17 +71,24 @@ var (
 	ErrRowKeyCount     = terror.ClassExecutor.New(codeRowKeyCount, "Wrong row key entry count")
 	ErrPrepareDDL      = terror.ClassExecutor.New(codePrepareDDL, "Can not prepare DDL statements")
 	ErrPasswordNoMatch = terror.ClassExecutor.New(CodePasswordNoMatch, "Can't find any matching row in the user table")
+
+	ErrAnalyzeInProgress  = terror.ClassExecutor.New(codeAnalyzeInProgress, "Table is being analyzed")
+	ErrAnalyzeCancelled   = terror.ClassExecutor.New(codeAnalyzeCancelled, "Analyze job is cancelled")
+	ErrAnalyzeJobNotFound = terror.ClassExecutor.New(codeAnalyzeJobNotFound, "Analyze job not found")
 )
 
 // Error codes.
 const (
-	codeUnknownPlan     terror.ErrCode = 1
-	codePrepareMulti    terror.ErrCode = 2
-	codeStmtNotFound    terror.ErrCode = 3
-	codeSchemaChanged   terror.ErrCode = 4
-	codeWrongParamCount terror.ErrCode = 5
-	codeRowKeyCount     terror.ErrCode = 6
-	codePrepareDDL      terror.ErrCode = 7
+	codeUnknownPlan        terror.ErrCode = 1
+	codePrepareMulti       terror.ErrCode = 2
+	codeStmtNotFound       terror.ErrCode = 3
+	codeSchemaChanged      terror.ErrCode = 4
+	codeWrongParamCount    terror.ErrCode = 5
+	codeRowKeyCount        terror.ErrCode = 6
+	codePrepareDDL         terror.ErrCode = 7
+	codeAnalyzeInProgress  terror.ErrCode = 8
+	codeAnalyzeCancelled   terror.ErrCode = 9
+	codeAnalyzeJobNotFound terror.ErrCode = 10
 	// MySQL error code
 	CodePasswordNoMatch terror.ErrCode = 1133
 	CodeCannotUser      terror.ErrCode = 1396
@@ -14,9 +14,13 @@
 package executor
 
 import (
+	"math/rand"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/types"
 )
 
 var _ = Suite(&testExecSuite{})
@@ -61,3 +65,34 @@ func (s *testExecSuite) TestMergeHandles(c *C) {
 		c.Assert(kr.EndKey, DeepEquals, ekr.EndKey)
 	}
 }
+
+func (s *testExecSuite) TestAnalyzeAddSample(c *C) {
+	aj := &analyzeJob{
+		cp:    &model.AnalyzeCheckpoint{},
+		dirty: make(map[int]struct{}),
+	}
+	for i := 0; i < maxSampleCount+100; i++ {
+		aj.addSample(types.MakeDatums(i))
+		aj.cp.Count++
+	}
+	c.Assert(aj.samples, HasLen, maxSampleCount)
+	c.Assert(aj.dirty, HasLen, maxSampleCount)
+
+	// Only the replaced samples are saved next time.
+	rand.Seed(1)
+	old := make([][]types.Datum, len(aj.samples))
+	copy(old, aj.samples)
+	aj.dirty = make(map[int]struct{})
+	for i := 0; i < 100; i++ {
+		aj.addSample(types.MakeDatums(maxSampleCount + 100 + i))
+		aj.cp.Count++
+	}
+	replaced := make(map[int]struct{})
+	for i := range aj.samples {
+		if aj.samples[i][0].GetInt64() != old[i][0].GetInt64() {
+			replaced[i] = struct{}{}
+		}
+	}
+	c.Assert(replaced, Not(HasLen), 0)
+	c.Assert(aj.dirty, DeepEquals, replaced)
+}
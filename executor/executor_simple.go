@@ -15,7 +15,6 @@ package executor
 
 import (
 	"fmt"
-	"math/rand"
 	"strings"
 
 	"github.com/juju/errors"
@@ -24,11 +23,10 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
-	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/plan/statistics"
-	"github.com/pingcap/tidb/plan/statscache"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
@@ -315,8 +313,13 @@ func (e *SimpleExec) executeFlushTable(s *ast.FlushTableStmt) error {
 }
 
 func (e *SimpleExec) executeAnalyzeTable(s *ast.AnalyzeTableStmt) error {
+	sessVars := e.ctx.GetSessionVars()
 	for _, table := range s.TableNames {
-		err := e.createStatisticsForTable(table)
+		dbName := table.Schema.O
+		if dbName == "" {
+			dbName = sessVars.CurrentDB
+		}
+		err := e.analyzeTable(dbName, table)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -324,102 +327,79 @@ func (e *SimpleExec) executeAnalyzeTable(s *ast.AnalyzeTableStmt) error {
 	return nil
 }
 
-const (
-	maxSampleCount     = 10000
-	defaultBucketCount = 256
-)
-
-func (e *SimpleExec) createStatisticsForTable(tn *ast.TableName) error {
-	var tableName string
-	if tn.Schema.L == "" {
-		tableName = tn.Name.L
-	} else {
-		tableName = tn.Schema.L + "." + tn.Name.L
-	}
-	sql := "select * from " + tableName
-	result, err := e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(e.ctx, sql)
+// analyzeTable analyzes a single table as an analyze job, the job is always finished
+// even if building the statistics panics.
+func (e *SimpleExec) analyzeTable(dbName string, tn *ast.TableName) (err error) {
+	tbl, ok := e.is.TableByID(tn.TableInfo.ID)
+	if !ok {
+		return infoschema.ErrTableNotExists.GenByArgs(dbName, tn.Name.O)
+	}
+	store := sessionctx.GetDomain(e.ctx).Store()
+	job, err := startAnalyzeJob(store, e.ctx.GetSessionVars().ConnectionID, dbName, tbl)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	count, samples, err := e.collectSamples(result)
-	result.Close()
+	defer func() {
+		if r := recover(); r != nil {
+			// The samples may be half updated, so the progress is not saved.
+			e.abortAnalyzeJob(job, errors.Errorf("%v", r), false)
+			panic(r)
+		}
+		if err != nil {
+			e.abortAnalyzeJob(job, err, true)
+		}
+	}()
+	err = job.collectSamples(e.ctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = e.buildStatisticsAndSaveToKV(tn, count, samples)
+	t, err := e.buildStatistics(tn.TableInfo, job)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	return errors.Trace(job.finish(t))
 }
 
-// collectSamples collects sample from the result set, using Reservoir Sampling algorithm.
-// See https://en.wikipedia.org/wiki/Reservoir_sampling
-func (e *SimpleExec) collectSamples(result ast.RecordSet) (count int64, samples []*ast.Row, err error) {
-	for {
-		var row *ast.Row
-		row, err = result.Next()
-		if err != nil {
-			return count, samples, errors.Trace(err)
-		}
-		if row == nil {
-			break
-		}
-		if len(samples) < maxSampleCount {
-			samples = append(samples, row)
-		} else {
-			shouldAdd := rand.Int63n(count) < maxSampleCount
-			if shouldAdd {
-				idx := rand.Intn(maxSampleCount)
-				samples[idx] = row
-			}
-		}
-		count++
+func (e *SimpleExec) abortAnalyzeJob(job *analyzeJob, err error, keepProgress bool) {
+	if err1 := job.abort(err, keepProgress); err1 != nil {
+		log.Errorf("[%d] abort analyze job %d error %v", e.ctx.GetSessionVars().ConnectionID, job.job.ID, errors.ErrorStack(err1))
 	}
-	return count, samples, nil
 }
 
-func (e *SimpleExec) buildStatisticsAndSaveToKV(tn *ast.TableName, count int64, sampleRows []*ast.Row) error {
+const (
+	maxSampleCount     = 10000
+	defaultBucketCount = 256
+)
+
+func (e *SimpleExec) buildStatistics(tblInfo *model.TableInfo, job *analyzeJob) (*statistics.Table, error) {
 	txn := e.ctx.Txn()
 	statBuilder := &statistics.Builder{
 		Sc:            e.ctx.GetSessionVars().StmtCtx,
-		TblInfo:       tn.TableInfo,
+		TblInfo:       tblInfo,
 		StartTS:       int64(txn.StartTS()),
-		Count:         count,
+		Count:         job.cp.Count,
 		NumBuckets:    defaultBucketCount,
-		ColumnSamples: rowsToColumnSamples(sampleRows),
+		ColumnSamples: rowsToColumnSamples(job.samples),
 		PkOffset:      -1,
+		Progress:      job.setProcessing,
 	}
 	for i := range statBuilder.ColumnSamples {
 		statBuilder.ColOffsets = append(statBuilder.ColOffsets, i)
 	}
 	t, err := statBuilder.NewTable()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	statscache.SetStatisticsTableCache(tn.TableInfo.ID, t)
-	tpb, err := t.ToPB()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	m := meta.NewMeta(txn)
-	err = m.SetTableStats(tn.TableInfo.ID, tpb)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	return nil
+	return t, errors.Trace(err)
 }
 
-func rowsToColumnSamples(rows []*ast.Row) [][]types.Datum {
+func rowsToColumnSamples(rows [][]types.Datum) [][]types.Datum {
 	if len(rows) == 0 {
 		return nil
 	}
-	columnSamples := make([][]types.Datum, len(rows[0].Data))
+	columnSamples := make([][]types.Datum, len(rows[0]))
 	for i := range columnSamples {
 		columnSamples[i] = make([]types.Datum, len(rows))
 	}
 	for j, row := range rows {
-		for i, val := range row.Data {
+		for i, val := range row {
 			columnSamples[i][j] = val
 		}
 	}
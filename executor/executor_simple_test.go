@@ -16,16 +16,24 @@ package executor_test
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/types"
 )
 
 func (s *testSuite) TestCharsetDatabase(c *C) {
@@ -233,3 +241,257 @@ func (s *testSuite) TestAnalyzeTable(c *C) {
 	rowStr = fmt.Sprintf("%s", result.Rows())
 	c.Check(strings.Split(rowStr, "{")[0], Equals, "[[TableScan_4 ")
 }
+
+func (s *testSuite) TestAnalyzeJobs(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists analyze_job_test")
+	tk.MustExec("create table analyze_job_test (a int, b int)")
+	tk.MustExec("insert into analyze_job_test values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("analyze table analyze_job_test")
+	rows := tk.MustQuery("admin show analyze jobs").Rows()
+	c.Assert(len(rows), Greater, 0)
+	last := rows[len(rows)-1]
+	c.Check(last[2], Equals, "test")
+	c.Check(last[3], Equals, "analyze_job_test")
+	c.Check(last[4], Equals, "finished")
+	c.Check(last[5], Equals, int64(3))
+	c.Check(last[6], Equals, "")
+	c.Check(last[8], Equals, "")
+
+	// A finished job can not be cancelled.
+	result := tk.MustQuery(fmt.Sprintf("admin cancel analyze jobs %d", last[0]))
+	c.Check(result.Rows()[0][1], Matches, ".*not found")
+}
+
+func (s *testSuite) getAnalyzeJobsAndCheckpoint(c *C, tableID int64) ([]*model.AnalyzeJob, *model.AnalyzeCheckpoint) {
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	defer txn.Rollback()
+	m := meta.NewMeta(txn)
+	jobs, err := m.GetAllAnalyzeJobs()
+	c.Assert(err, IsNil)
+	cp, err := m.GetAnalyzeCheckpoint(tableID)
+	c.Assert(err, IsNil)
+	return jobs, cp
+}
+
+// addAnalyzeJob adds a running analyze job with its checkpoint and sampled rows, as if it is run by another server.
+func (s *testSuite) addAnalyzeJob(c *C, tableID int64, lastUpdate time.Time, cp *model.AnalyzeCheckpoint, samples [][]types.Datum) int64 {
+	var id int64
+	err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		var err1 error
+		id, err1 = m.GenGlobalID()
+		c.Assert(err1, IsNil)
+		job := &model.AnalyzeJob{
+			ID:           id,
+			TableID:      tableID,
+			State:        model.AnalyzeJobRunning,
+			RowCount:     cp.Count,
+			LastUpdateTS: lastUpdate.UnixNano(),
+		}
+		c.Assert(m.SetAnalyzeJob(job), IsNil)
+		cp.JobID = id
+		c.Assert(m.SetAnalyzeCheckpoint(tableID, cp), IsNil)
+		for i, row := range samples {
+			b, err2 := tablecodec.EncodeRow(row, cp.ColumnIDs)
+			c.Assert(err2, IsNil)
+			c.Assert(m.SetAnalyzeSample(tableID, int64(i), b), IsNil)
+		}
+		return nil
+	})
+	c.Assert(err, IsNil)
+	return id
+}
+
+func (s *testSuite) getTableStatsCount(c *C, tableID int64) int64 {
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	defer txn.Rollback()
+	tpb, err := meta.NewMeta(txn).GetTableStats(tableID)
+	c.Assert(err, IsNil)
+	return tpb.GetCount()
+}
+
+func (s *testSuite) TestAnalyzeJobResume(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists analyze_resume_test")
+	tk.MustExec("create table analyze_resume_test (a int primary key, b int)")
+	tk.MustExec("insert into analyze_resume_test values (1, 1), (2, 2), (3, 3), (4, 4)")
+	is := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("analyze_resume_test"))
+	c.Assert(err, IsNil)
+	tableID := tbl.Meta().ID
+	colIDs := []int64{tbl.Cols()[0].ID, tbl.Cols()[1].ID}
+
+	// A job is interrupted after sampling the first two rows.
+	interrupted := time.Now().Add(-time.Hour)
+	cp := &model.AnalyzeCheckpoint{ColumnIDs: colIDs, Handle: 2, Count: 2, SampleCount: 2, LastUpdateTS: time.Now().UnixNano()}
+	oldID := s.addAnalyzeJob(c, tableID, interrupted, cp, [][]types.Datum{types.MakeDatums(1, 1), types.MakeDatums(2, 2)})
+
+	tk.MustExec("analyze table analyze_resume_test")
+	jobs, cp := s.getAnalyzeJobsAndCheckpoint(c, tableID)
+	c.Assert(cp, IsNil)
+	last := jobs[len(jobs)-1]
+	c.Assert(last.State, Equals, model.AnalyzeJobDone)
+	c.Assert(last.ResumedFrom, Equals, oldID)
+	c.Assert(last.RowCount, Equals, int64(4))
+	for _, job := range jobs {
+		if job.ID == oldID {
+			c.Assert(job.State, Equals, model.AnalyzeJobFailed)
+			c.Assert(job.Error, Equals, "interrupted")
+		}
+	}
+	c.Assert(s.getTableStatsCount(c, tableID), Equals, int64(4))
+
+	// An expired checkpoint is dropped and the table is sampled from the start.
+	cp = &model.AnalyzeCheckpoint{ColumnIDs: colIDs, Handle: 2, Count: 100, SampleCount: 1, LastUpdateTS: interrupted.UnixNano()}
+	s.addAnalyzeJob(c, tableID, interrupted, cp, [][]types.Datum{types.MakeDatums(1, 1)})
+
+	tk.MustExec("analyze table analyze_resume_test")
+	jobs, cp = s.getAnalyzeJobsAndCheckpoint(c, tableID)
+	c.Assert(cp, IsNil)
+	last = jobs[len(jobs)-1]
+	c.Assert(last.State, Equals, model.AnalyzeJobDone)
+	c.Assert(last.ResumedFrom, Equals, int64(0))
+	c.Assert(last.RowCount, Equals, int64(4))
+	c.Assert(s.getTableStatsCount(c, tableID), Equals, int64(4))
+}
+
+func (s *testSuite) TestAnalyzeCheckpointDropped(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists analyze_drop_test")
+	tk.MustExec("create table analyze_drop_test (a int)")
+	getTableID := func() int64 {
+		is := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema()
+		tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("analyze_drop_test"))
+		c.Assert(err, IsNil)
+		return tbl.Meta().ID
+	}
+	sample := [][]types.Datum{types.MakeDatums(1)}
+	// The background job that deletes the table data conflicts with the next DDL job, and the
+	// failed transaction leaves its locks, so wait for it to finish.
+	waitBgJobs := func() {
+		for i := 0; i < 100; i++ {
+			var n int64
+			err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+				var err1 error
+				n, err1 = meta.NewMeta(txn).BgJobQueueLen()
+				return errors.Trace(err1)
+			})
+			c.Assert(err, IsNil)
+			if n == 0 {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		c.Fatal("background jobs are not finished")
+	}
+	// The dropped table's background job may lock the meta keys after the analyze ones, so get
+	// them one by one instead of scanning.
+	getJob := func(id, tableID int64) (*model.AnalyzeJob, *model.AnalyzeCheckpoint, [][]byte) {
+		txn, err := s.store.Begin()
+		c.Assert(err, IsNil)
+		defer txn.Rollback()
+		m := meta.NewMeta(txn)
+		job, err := m.GetAnalyzeJob(id)
+		c.Assert(err, IsNil)
+		cp, err := m.GetAnalyzeCheckpoint(tableID)
+		c.Assert(err, IsNil)
+		rows, err := m.GetAnalyzeSamples(tableID)
+		c.Assert(err, IsNil)
+		return job, cp, rows
+	}
+
+	// The checkpoint of a failed job is dropped when the table is truncated.
+	tableID := getTableID()
+	cp := &model.AnalyzeCheckpoint{ColumnIDs: []int64{1}, Count: 1, SampleCount: 1, LastUpdateTS: time.Now().UnixNano()}
+	id := s.addAnalyzeJob(c, tableID, time.Now().Add(-time.Hour), cp, sample)
+	tk.MustExec("truncate table analyze_drop_test")
+	waitBgJobs()
+	_, cp, rows := getJob(id, tableID)
+	c.Assert(cp, IsNil)
+	c.Assert(rows, HasLen, 0)
+
+	// The job still analyzing a dropped table is cancelled.
+	tableID = getTableID()
+	cp = &model.AnalyzeCheckpoint{ColumnIDs: []int64{1}, Count: 1, SampleCount: 1, LastUpdateTS: time.Now().UnixNano()}
+	id = s.addAnalyzeJob(c, tableID, time.Now(), cp, sample)
+	tk.MustExec("drop table analyze_drop_test")
+	waitBgJobs()
+	job, cp, rows := getJob(id, tableID)
+	c.Assert(cp, IsNil)
+	c.Assert(rows, HasLen, 0)
+	c.Assert(job.State, Equals, model.AnalyzeJobCancelled)
+}
+
+func (s *testSuite) TestAnalyzeJobCancel(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists analyze_cancel_test")
+	tk.MustExec("create table analyze_cancel_test (a int)")
+	is := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("analyze_cancel_test"))
+	c.Assert(err, IsNil)
+	tableID := tbl.Meta().ID
+
+	// Make a job that is running on another server.
+	job := &model.AnalyzeJob{
+		TableID:      tableID,
+		State:        model.AnalyzeJobRunning,
+		LastUpdateTS: time.Now().UnixNano(),
+	}
+	setJob := func() {
+		err1 := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+			m := meta.NewMeta(txn)
+			if job.ID == 0 {
+				id, err2 := m.GenGlobalID()
+				c.Assert(err2, IsNil)
+				job.ID = id
+				cp := &model.AnalyzeCheckpoint{JobID: id}
+				c.Assert(m.SetAnalyzeCheckpoint(tableID, cp), IsNil)
+			}
+			return m.SetAnalyzeJob(job)
+		})
+		c.Assert(err1, IsNil)
+	}
+	setJob()
+
+	_, err = tk.Exec("analyze table analyze_cancel_test")
+	c.Assert(terror.ErrorEqual(err, executor.ErrAnalyzeInProgress), IsTrue)
+
+	// The running job is asked to stop.
+	tk.MustQuery(fmt.Sprintf("admin cancel analyze jobs %d", job.ID)).Check(testkit.Rows(fmt.Sprintf("%d successful", job.ID)))
+	jobs, cp := s.getAnalyzeJobsAndCheckpoint(c, tableID)
+	c.Assert(cp, NotNil)
+	last := jobs[len(jobs)-1]
+	c.Assert(last.ID, Equals, job.ID)
+	c.Assert(last.State, Equals, model.AnalyzeJobRunning)
+	c.Assert(last.Cancelling, IsTrue)
+
+	// An interrupted job is shown as interrupted and is cancelled at once.
+	job.LastUpdateTS = time.Now().Add(-time.Hour).UnixNano()
+	setJob()
+	found := false
+	for _, row := range tk.MustQuery("admin show analyze jobs").Rows() {
+		if fmt.Sprint(row[0]) == fmt.Sprint(job.ID) {
+			c.Assert(fmt.Sprint(row[4]), Equals, "interrupted")
+			found = true
+		}
+	}
+	c.Assert(found, IsTrue)
+	tk.MustQuery(fmt.Sprintf("admin cancel analyze jobs %d", job.ID)).Check(testkit.Rows(fmt.Sprintf("%d successful", job.ID)))
+	jobs, cp = s.getAnalyzeJobsAndCheckpoint(c, tableID)
+	c.Assert(cp, IsNil)
+	last = jobs[len(jobs)-1]
+	c.Assert(last.State, Equals, model.AnalyzeJobCancelled)
+
+	tk.MustExec("analyze table analyze_cancel_test")
+}
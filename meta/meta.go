@@ -766,6 +766,118 @@ func (m *Meta) SetSchemaDiff(diff *model.SchemaDiff) error {
 	return errors.Trace(err)
 }
 
+// Analyze job structure
+//	AnalyzeJobs: hash, job ID -> analyze job
+//	AnalyzeCheckpoints: hash, table ID -> checkpoint of the last unfinished analyze job
+//	ASamples:1 -> hash, sample index -> sampled row of the checkpoint of table 1
+
+var (
+	mAnalyzeJobsKey        = []byte("AnalyzeJobs")
+	mAnalyzeCheckpointsKey = []byte("AnalyzeCheckpoints")
+	mAnalyzeSamplesPrefix  = "ASamples"
+)
+
+// SetAnalyzeJob adds or updates an analyze job.
+func (m *Meta) SetAnalyzeJob(job *model.AnalyzeJob) error {
+	b, err := job.Encode()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mAnalyzeJobsKey, m.jobIDKey(job.ID), b)
+}
+
+// GetAnalyzeJob gets an analyze job, it returns nil if the job doesn't exist.
+func (m *Meta) GetAnalyzeJob(id int64) (*model.AnalyzeJob, error) {
+	value, err := m.txn.HGet(mAnalyzeJobsKey, m.jobIDKey(id))
+	if err != nil || value == nil {
+		return nil, errors.Trace(err)
+	}
+
+	job := &model.AnalyzeJob{}
+	err = job.Decode(value)
+	return job, errors.Trace(err)
+}
+
+// GetAllAnalyzeJobs gets all analyze jobs ordered by job ID.
+func (m *Meta) GetAllAnalyzeJobs() ([]*model.AnalyzeJob, error) {
+	pairs, err := m.txn.HGetAll(mAnalyzeJobsKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The job ID key is encoded in big endian, so the jobs are already ordered.
+	jobs := make([]*model.AnalyzeJob, 0, len(pairs))
+	for _, pair := range pairs {
+		job := &model.AnalyzeJob{}
+		err = job.Decode(pair.Value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// DelAnalyzeJob deletes an analyze job.
+func (m *Meta) DelAnalyzeJob(id int64) error {
+	return m.txn.HDel(mAnalyzeJobsKey, m.jobIDKey(id))
+}
+
+func (m *Meta) analyzeCheckpointKey(tableID int64) []byte {
+	return []byte(fmt.Sprintf("%d", tableID))
+}
+
+func (m *Meta) analyzeSamplesKey(tableID int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mAnalyzeSamplesPrefix, tableID))
+}
+
+// SetAnalyzeCheckpoint sets the analyze checkpoint of the table.
+func (m *Meta) SetAnalyzeCheckpoint(tableID int64, cp *model.AnalyzeCheckpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mAnalyzeCheckpointsKey, m.analyzeCheckpointKey(tableID), b)
+}
+
+// GetAnalyzeCheckpoint gets the analyze checkpoint of the table, it returns nil if there is no checkpoint.
+func (m *Meta) GetAnalyzeCheckpoint(tableID int64) (*model.AnalyzeCheckpoint, error) {
+	value, err := m.txn.HGet(mAnalyzeCheckpointsKey, m.analyzeCheckpointKey(tableID))
+	if err != nil || value == nil {
+		return nil, errors.Trace(err)
+	}
+
+	cp := &model.AnalyzeCheckpoint{}
+	err = json.Unmarshal(value, cp)
+	return cp, errors.Trace(err)
+}
+
+// DelAnalyzeCheckpoint deletes the analyze checkpoint of the table and its sampled rows.
+func (m *Meta) DelAnalyzeCheckpoint(tableID int64) error {
+	err := m.txn.HDel(mAnalyzeCheckpointsKey, m.analyzeCheckpointKey(tableID))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HClear(m.analyzeSamplesKey(tableID))
+}
+
+// SetAnalyzeSample sets the sampled row at index idx of the table's analyze checkpoint.
+func (m *Meta) SetAnalyzeSample(tableID int64, idx int64, row []byte) error {
+	return m.txn.HSet(m.analyzeSamplesKey(tableID), m.jobIDKey(idx), row)
+}
+
+// GetAnalyzeSamples gets the sampled rows of the table's analyze checkpoint ordered by index.
+func (m *Meta) GetAnalyzeSamples(tableID int64) ([][]byte, error) {
+	pairs, err := m.txn.HGetAll(m.analyzeSamplesKey(tableID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows := make([][]byte, 0, len(pairs))
+	for _, pair := range pairs {
+		rows = append(rows, pair.Value)
+	}
+	return rows, nil
+}
+
 // meta error codes.
 const (
 	codeInvalidTableKey terror.ErrCode = 1
@@ -319,3 +319,64 @@ func (s *testSuite) TestDDL(c *C) {
 	err = txn.Commit()
 	c.Assert(err, IsNil)
 }
+
+func (s *testSuite) TestAnalyzeJob(c *C) {
+	defer testleak.AfterTest(c)()
+	driver := localstore.Driver{Driver: goleveldb.MemoryDriver{}}
+	store, err := driver.Open("memory")
+	c.Assert(err, IsNil)
+	defer store.Close()
+
+	txn, err := store.Begin()
+	c.Assert(err, IsNil)
+
+	defer txn.Rollback()
+
+	t := meta.NewMeta(txn)
+
+	for _, id := range []int64{300, 2, 10} {
+		err = t.SetAnalyzeJob(&model.AnalyzeJob{ID: id, TableID: 1, State: model.AnalyzeJobRunning})
+		c.Assert(err, IsNil)
+	}
+	job, err := t.GetAnalyzeJob(10)
+	c.Assert(err, IsNil)
+	c.Assert(job.IsRunning(), IsTrue)
+	job.State = model.AnalyzeJobDone
+	err = t.SetAnalyzeJob(job)
+	c.Assert(err, IsNil)
+	err = t.DelAnalyzeJob(2)
+	c.Assert(err, IsNil)
+	job, err = t.GetAnalyzeJob(2)
+	c.Assert(err, IsNil)
+	c.Assert(job, IsNil)
+
+	jobs, err := t.GetAllAnalyzeJobs()
+	c.Assert(err, IsNil)
+	c.Assert(jobs, HasLen, 2)
+	c.Assert(jobs[0].ID, Equals, int64(10))
+	c.Assert(jobs[0].State, Equals, model.AnalyzeJobDone)
+	c.Assert(jobs[1].ID, Equals, int64(300))
+
+	cp := &model.AnalyzeCheckpoint{JobID: 300, ColumnIDs: []int64{1, 2}, Handle: 5, Count: 3, SampleCount: 3}
+	err = t.SetAnalyzeCheckpoint(1, cp)
+	c.Assert(err, IsNil)
+	v, err := t.GetAnalyzeCheckpoint(1)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, cp)
+	for _, idx := range []int64{2, 0, 1} {
+		err = t.SetAnalyzeSample(1, idx, []byte{byte(idx)})
+		c.Assert(err, IsNil)
+	}
+	rows, err := t.GetAnalyzeSamples(1)
+	c.Assert(err, IsNil)
+	c.Assert(rows, DeepEquals, [][]byte{{0}, {1}, {2}})
+
+	err = t.DelAnalyzeCheckpoint(1)
+	c.Assert(err, IsNil)
+	v, err = t.GetAnalyzeCheckpoint(1)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+	rows, err = t.GetAnalyzeSamples(1)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 0)
+}
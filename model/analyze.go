@@ -0,0 +1,111 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// AnalyzeJobState is for analyze job state.
+type AnalyzeJobState byte
+
+// List analyze job states.
+const (
+	AnalyzeJobNone AnalyzeJobState = iota
+	AnalyzeJobRunning
+	AnalyzeJobDone
+	AnalyzeJobCancelled
+	AnalyzeJobFailed
+)
+
+// String implements fmt.Stringer interface.
+func (s AnalyzeJobState) String() string {
+	switch s {
+	case AnalyzeJobRunning:
+		return "running"
+	case AnalyzeJobDone:
+		return "finished"
+	case AnalyzeJobCancelled:
+		return "cancelled"
+	case AnalyzeJobFailed:
+		return "failed"
+	default:
+		return "none"
+	}
+}
+
+// AnalyzeJob is for analyzing a table, it is saved in meta so every server can show and cancel it.
+type AnalyzeJob struct {
+	ID         int64           `json:"id"`
+	ConnID     uint64          `json:"conn_id"`
+	SchemaName string          `json:"schema_name"`
+	TableName  string          `json:"table_name"`
+	TableID    int64           `json:"table_id"`
+	State      AnalyzeJobState `json:"state"`
+	// The number of rows that are scanned.
+	RowCount int64 `json:"row_count"`
+	// The column or index whose statistics is being built.
+	Processing string `json:"processing"`
+	Error      string `json:"err"`
+	// Cancelling is set when the job is asked to stop, the running job checks it every time it saves its progress.
+	Cancelling bool `json:"cancelling"`
+	// ResumedFrom is the ID of the interrupted job whose samples this job continues with.
+	ResumedFrom int64 `json:"resumed_from"`
+	// unix nano seconds
+	StartTS      int64 `json:"start_ts"`
+	LastUpdateTS int64 `json:"last_update_ts"`
+}
+
+// Encode encodes analyze job with json format.
+func (job *AnalyzeJob) Encode() ([]byte, error) {
+	b, err := json.Marshal(job)
+	return b, errors.Trace(err)
+}
+
+// Decode decodes analyze job from the json buffer.
+func (job *AnalyzeJob) Decode(b []byte) error {
+	err := json.Unmarshal(b, job)
+	return errors.Trace(err)
+}
+
+// IsRunning returns whether job is still running or not.
+func (job *AnalyzeJob) IsRunning() bool {
+	return job.State == AnalyzeJobRunning
+}
+
+// String implements fmt.Stringer interface.
+func (job *AnalyzeJob) String() string {
+	return fmt.Sprintf("ID:%d, State:%s, Table:%s.%s, RowCount:%d, Processing:%s",
+		job.ID, job.State, job.SchemaName, job.TableName, job.RowCount, job.Processing)
+}
+
+// AnalyzeCheckpoint is the sampling progress of an analyze job. It is kept after the job
+// is interrupted, so the next analyze job on the table continues from it instead of sampling
+// the whole table again. The sampled rows are saved separately.
+type AnalyzeCheckpoint struct {
+	JobID int64 `json:"job_id"`
+	// ColumnIDs are the columns in sampled rows, the checkpoint is useless once they change.
+	ColumnIDs []int64 `json:"column_ids"`
+	// Handle is the handle of the last scanned row.
+	Handle int64 `json:"handle"`
+	// Count is the number of scanned rows.
+	Count int64 `json:"count"`
+	// SampleCount is the number of sampled rows.
+	SampleCount int64 `json:"sample_count"`
+	// LastUpdateTS is the time the checkpoint is saved, in unix nano seconds.
+	LastUpdateTS int64 `json:"last_update_ts"`
+}
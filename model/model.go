@@ -146,6 +146,15 @@ const (
 	IndexTypeHash
 )
 
+// Normalize returns the actual type of an index of type t.
+// Indices created without specifying the index type have type zero, they are btree indices.
+func (t IndexType) Normalize() IndexType {
+	if t == 0 {
+		return IndexTypeBtree
+	}
+	return t
+}
+
 // IndexInfo provides meta data describing a DB index.
 // It corresponds to the statement `CREATE INDEX Name ON Table (Column);`
 // See https://dev.mysql.com/doc/refman/5.7/en/create-index.html
@@ -158,6 +158,7 @@ var tokenMap = map[string]int{
 	"BTREE":               btree,
 	"BY":                  by,
 	"BYTE":                byteType,
+	"CANCEL":              cancel,
 	"CASE":                caseKwd,
 	"CAST":                cast,
 	"CEIL":                ceil,
@@ -281,6 +282,7 @@ var tokenMap = map[string]int{
 	"IS":                  is,
 	"ISNULL":              isNull,
 	"ISOLATION":           isolation,
+	"JOBS":                jobs,
 	"JOIN":                join,
 	"KEY":                 key,
 	"KEY_BLOCK_SIZE":      keyBlockSize,
@@ -320,6 +320,7 @@ import (
 	boolType	"BOOL"
 	btree		"BTREE"
 	byteType	"BYTE"
+	cancel		"CANCEL"
 	charsetKwd	"CHARSET"
 	checksum	"CHECKSUM"
 	collation	"COLLATION"
@@ -357,6 +358,7 @@ import (
 	identified	"IDENTIFIED"
 	isolation	"ISOLATION"
 	indexes		"INDEXES"
+	jobs		"JOBS"
 	keyBlockSize	"KEY_BLOCK_SIZE"
 	local		"LOCAL"
 	less		"LESS"
@@ -443,7 +445,7 @@ import (
 	userVar		"USER_VAR"
 
 %type   <item>
-	AdminStmt		"Check table statement or show ddl statement"
+	AdminStmt		"Check table statement, show ddl statement or analyze job statement"
 	AlterTableStmt		"Alter table statement"
 	AlterTableSpec		"Alter table specification"
 	AlterTableSpecList	"Alter table specification list"
@@ -683,6 +685,7 @@ import (
 	OptCharset		"Optional Character setting"
 	OptCollate		"Optional Collate setting"
 	NUM			"numbers"
+	NumList			"Num list"
 	LengthNum		"Field length num(uint64)"
 
 %type	<ident>
@@ -1686,6 +1689,16 @@ ExplainStmt:
 		$$ = &ast.ExplainStmt{Stmt: $2.(ast.StmtNode)}
 	}
 
+NumList:
+	LengthNum
+	{
+		$$ = []int64{int64($1.(uint64))}
+	}
+|	NumList ',' LengthNum
+	{
+		$$ = append($1.([]int64), int64($3.(uint64)))
+	}
+
 LengthNum:
 	NUM
 	{
@@ -2114,7 +2127,7 @@ UnReservedKeyword:
 | "MIN_ROWS" | "NATIONAL" | "ROW" | "ROW_FORMAT" | "QUARTER" | "GRANTS" | "TRIGGERS" | "DELAY_KEY_WRITE" | "ISOLATION"
 | "REPEATABLE" | "COMMITTED" | "UNCOMMITTED" | "ONLY" | "SERIALIZABLE" | "LEVEL" | "VARIABLES" | "SQL_CACHE" | "INDEXES" | "PROCESSLIST"
 | "SQL_NO_CACHE" | "DISABLE"  | "ENABLE" | "REVERSE" | "SPACE" | "PRIVILEGES" | "NO" | "BINLOG" | "FUNCTION" | "VIEW" | "MODIFY" | "EVENTS" | "PARTITIONS"
-| "TIMESTAMPDIFF"
+| "TIMESTAMPDIFF" | "CANCEL" | "JOBS"
 
 ReservedKeyword:
 "ADD" | "ALL" | "ALTER" | "ANALYZE" | "AND" | "AS" | "ASC" | "BETWEEN" | "BIGINT"
@@ -4250,6 +4263,17 @@ AdminStmt:
 			Tables: $4.([]*ast.TableName),
 		}
 	}
+|	"ADMIN" "SHOW" "ANALYZE" "JOBS"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminShowAnalyzeJobs}
+	}
+|	"ADMIN" "CANCEL" "ANALYZE" "JOBS" NumList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:	ast.AdminCancelAnalyzeJobs,
+			JobIDs:	$5.([]int64),
+		}
+	}
 
 /****************************Show Statement*******************************/
 ShowStmt:
@@ -0,0 +1,56 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/model"
+)
+
+// accessPathBuilder builds the physical plan that reads a data source through an index.
+// It works on the planner's internal plans, so access paths can only be registered inside this package
+// and are not pluggable like the IndexCreators of package table. An index is read only if its type
+// has both an accessPathBuilder and an IndexCreator, and the executor reads it like a btree index.
+type accessPathBuilder func(p *DataSource, prop *requiredProperty, index *model.IndexInfo) (*physicalPlanInfo, error)
+
+var accessPathBuilders = make(map[model.IndexType]accessPathBuilder)
+
+// registerAccessPath registers the accessPathBuilder for indices of the index type.
+// Indices of types without a registered builder are never used to read the data source.
+// It returns an error if the index type is already registered.
+func registerAccessPath(tp model.IndexType, builder accessPathBuilder) error {
+	tp = tp.Normalize()
+	if _, ok := accessPathBuilders[tp]; ok {
+		return errors.Errorf("access path of index type %d is already registered", tp)
+	}
+	accessPathBuilders[tp] = builder
+	return nil
+}
+
+// mustRegisterAccessPath is like registerAccessPath but panics if the index type is already registered.
+func mustRegisterAccessPath(tp model.IndexType, builder accessPathBuilder) {
+	if err := registerAccessPath(tp, builder); err != nil {
+		panic(err)
+	}
+}
+
+func getAccessPathBuilder(tp model.IndexType) (accessPathBuilder, bool) {
+	builder, ok := accessPathBuilders[tp.Normalize()]
+	return builder, ok
+}
+
+func init() {
+	mustRegisterAccessPath(model.IndexTypeBtree, (*DataSource).convert2IndexScan)
+	mustRegisterAccessPath(model.IndexTypeHash, (*DataSource).convert2IndexScan)
+}
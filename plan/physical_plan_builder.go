@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -248,7 +249,15 @@ func (p *DataSource) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlan
 	}
 	if !includeTableScan || p.need2ConsiderIndex(prop) {
 		for _, index := range indices {
-			indexInfo, err := p.convert2IndexScan(prop, index)
+			buildAccessPath, ok := getAccessPathBuilder(index.Tp)
+			if !ok {
+				continue
+			}
+			// The index data is not maintained without an IndexCreator of its type.
+			if _, ok = table.GetIndexCreator(index.Tp); !ok {
+				continue
+			}
+			indexInfo, err := buildAccessPath(p, prop, index)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -256,6 +265,13 @@ func (p *DataSource) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlan
 				info = indexInfo
 			}
 		}
+		// None of the hinted indices can be used to read the data source.
+		if info == nil {
+			info, err = p.convert2TableScan(prop)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	}
 	return info, errors.Trace(p.storePlanInfo(prop, info))
 }
@@ -798,3 +798,56 @@ func (s *testPlanSuite) TestPhysicalInitialize(c *C) {
 		c.Assert(ToString(pp), Equals, ca.ans, Commentf("for %s", ca.sql))
 	}
 }
+
+func (s *testPlanSuite) TestAccessPathRegistry(c *C) {
+	defer testleak.AfterTest(c)()
+	// Indices in the mocked table are btree indices, they can not be used to read the table
+	// if btree has no access path builder.
+	origin := accessPathBuilders
+	accessPathBuilders = make(map[model.IndexType]accessPathBuilder)
+	defer func() { accessPathBuilders = origin }()
+	mustRegisterAccessPath(model.IndexTypeHash, (*DataSource).convert2IndexScan)
+	c.Assert(registerAccessPath(model.IndexTypeHash, nil), NotNil)
+	_, ok := getAccessPathBuilder(0)
+	c.Assert(ok, IsFalse)
+
+	cases := []struct {
+		sql  string
+		best string
+	}{
+		{
+			sql:  "select * from t where t.c = 1",
+			best: "Table(t)",
+		},
+		{
+			sql:  "select * from t t1 use index(c_d_e)",
+			best: "Table(t)",
+		},
+	}
+	for _, ca := range cases {
+		comment := Commentf("for %s", ca.sql)
+		stmt, err := s.ParseOneStmt(ca.sql, "", "")
+		c.Assert(err, IsNil, comment)
+
+		is, err := mockResolve(stmt)
+		c.Assert(err, IsNil)
+
+		builder := &planBuilder{
+			allocator: new(idAllocator),
+			ctx:       mockContext(),
+			colMapper: make(map[*ast.ColumnNameExpr]int),
+			is:        is,
+		}
+		p := builder.build(stmt)
+		c.Assert(builder.err, IsNil)
+		lp := p.(LogicalPlan)
+
+		_, lp, err = lp.PredicatePushDown(nil)
+		c.Assert(err, IsNil)
+		lp.PruneColumns(lp.GetSchema().Columns)
+		lp.ResolveIndicesAndCorCols()
+		info, err := lp.convert2PhysicalPlan(&requiredProperty{})
+		c.Assert(err, IsNil)
+		c.Assert(ToString(EliminateProjection(info.p)), Equals, ca.best, comment)
+	}
+}
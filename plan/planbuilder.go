@@ -326,6 +326,12 @@ func (b *planBuilder) buildAdmin(as *ast.AdminStmt) Plan {
 	case ast.AdminShowDDL:
 		p = &ShowDDL{}
 		p.SetSchema(buildShowDDLFields())
+	case ast.AdminShowAnalyzeJobs:
+		p = &ShowAnalyzeJobs{}
+		p.SetSchema(buildShowAnalyzeJobsFields())
+	case ast.AdminCancelAnalyzeJobs:
+		p = &CancelAnalyzeJobs{JobIDs: as.JobIDs}
+		p.SetSchema(buildCancelAnalyzeJobsFields())
 	default:
 		b.err = ErrUnsupportedType.Gen("Unsupported type %T", as)
 	}
@@ -345,6 +351,29 @@ func buildShowDDLFields() expression.Schema {
 	return schema
 }
 
+func buildShowAnalyzeJobsFields() expression.Schema {
+	schema := expression.NewSchema(make([]*expression.Column, 0, 9))
+	schema.Append(buildColumn("", "JOB_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumn("", "CONN_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumn("", "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	schema.Append(buildColumn("", "TABLE_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumn("", "STATE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumn("", "ROWS_SCANNED", mysql.TypeLonglong, 4))
+	schema.Append(buildColumn("", "PROCESSING", mysql.TypeVarchar, 64))
+	schema.Append(buildColumn("", "START_TIME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumn("", "ERROR", mysql.TypeVarchar, 128))
+
+	return schema
+}
+
+func buildCancelAnalyzeJobsFields() expression.Schema {
+	schema := expression.NewSchema(make([]*expression.Column, 0, 2))
+	schema.Append(buildColumn("", "JOB_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumn("", "RESULT", mysql.TypeVarchar, 128))
+
+	return schema
+}
+
 func buildColumn(tableName, name string, tp byte, size int) *expression.Column {
 	cs, cl := types.DefaultCharsetForType(tp)
 	flag := mysql.UnsignedFlag
@@ -42,6 +42,18 @@ type CheckTable struct {
 	Tables []*ast.TableName
 }
 
+// ShowAnalyzeJobs is for showing analyze jobs, built from the 'admin show analyze jobs' statement.
+type ShowAnalyzeJobs struct {
+	basePlan
+}
+
+// CancelAnalyzeJobs is used for cancelling analyze jobs, built from the 'admin cancel analyze jobs' statement.
+type CancelAnalyzeJobs struct {
+	basePlan
+
+	JobIDs []int64
+}
+
 // IndexRange represents an index range to be scanned.
 type IndexRange struct {
 	LowVal      []types.Datum
@@ -508,6 +508,16 @@ type Builder struct {
 	IndOffsets    []int                      // IndOffsets is the offset of indexes in the table.
 	PkRecords     ast.RecordSet              // PkRecords is the record set of primary key of integer type.
 	PkOffset      int                        // PkOffset is the offset of primary key of integer type in the table.
+	// Progress is called with the name of the column or index before building its histogram.
+	// If it returns an error, building the table statistics is aborted with that error.
+	Progress func(name string) error
+}
+
+func (b *Builder) reportProgress(name string) error {
+	if b.Progress == nil {
+		return nil
+	}
+	return errors.Trace(b.Progress(name))
 }
 
 // NewTable creates a table statistics.
@@ -523,19 +533,31 @@ func (b *Builder) NewTable() (*Table, error) {
 		Indices: make([]*Column, len(b.TblInfo.Indices)),
 	}
 	for i, offset := range b.ColOffsets {
-		err := t.buildColumn(b.Sc, offset, b.ColumnSamples[i], b.NumBuckets)
+		err := b.reportProgress(b.TblInfo.Columns[offset].Name.O)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = t.buildColumn(b.Sc, offset, b.ColumnSamples[i], b.NumBuckets)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
 	if b.PkOffset != -1 {
-		err := t.build4SortedColumn(b.Sc, b.PkOffset, b.PkRecords, b.NumBuckets, true)
+		err := b.reportProgress(b.TblInfo.Columns[b.PkOffset].Name.O)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = t.build4SortedColumn(b.Sc, b.PkOffset, b.PkRecords, b.NumBuckets, true)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
 	for i, offset := range b.IndOffsets {
-		err := t.build4SortedColumn(b.Sc, offset, b.IndRecords[i], b.NumBuckets, false)
+		err := b.reportProgress(b.TblInfo.Indices[offset].Name.O)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = t.build4SortedColumn(b.Sc, offset, b.IndRecords[i], b.NumBuckets, false)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
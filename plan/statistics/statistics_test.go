@@ -17,6 +17,7 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/model"
@@ -221,6 +222,44 @@ func (s *testStatisticsSuite) TestTable(c *C) {
 	c.Check(nt.String(), Equals, str)
 }
 
+func (s *testStatisticsSuite) TestBuilderProgress(c *C) {
+	tblInfo := &model.TableInfo{
+		ID: 1,
+		Columns: []*model.ColumnInfo{
+			{
+				ID:        2,
+				Name:      model.NewCIStr("a"),
+				FieldType: *types.NewFieldType(mysql.TypeLonglong),
+			},
+		},
+	}
+	var processed []string
+	builder := &Builder{
+		Sc:            new(variable.StatementContext),
+		TblInfo:       tblInfo,
+		Count:         s.count,
+		NumBuckets:    256,
+		ColumnSamples: [][]types.Datum{s.samples},
+		ColOffsets:    []int{0},
+		PkOffset:      -1,
+		Progress: func(name string) error {
+			processed = append(processed, name)
+			return nil
+		},
+	}
+	_, err := builder.NewTable()
+	c.Check(err, IsNil)
+	c.Check(processed, DeepEquals, []string{"a"})
+
+	abortErr := errors.New("abort")
+	builder.Progress = func(name string) error {
+		return abortErr
+	}
+	t, err := builder.NewTable()
+	c.Check(errors.Cause(err), Equals, abortErr)
+	c.Check(t, IsNil)
+}
+
 func (s *testStatisticsSuite) TestPseudoTable(c *C) {
 	ti := &model.TableInfo{}
 	ti.Columns = append(ti.Columns, &model.ColumnInfo{
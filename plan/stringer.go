@@ -36,6 +36,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 
 	var str string
 	switch x := in.(type) {
+	case *CancelAnalyzeJobs:
+		str = "CancelAnalyzeJobs"
 	case *CheckTable:
 		str = "CheckTable"
 	case *PhysicalIndexScan:
@@ -86,6 +88,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = "Limit"
 	case *SelectLock:
 		str = "Lock"
+	case *ShowAnalyzeJobs:
+		str = "ShowAnalyzeJobs"
 	case *ShowDDL:
 		str = "ShowDDL"
 	case *Sort:
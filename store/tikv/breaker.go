@@ -0,0 +1,158 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/ngaut/log"
+)
+
+// errStoreUnavailable is returned without sending the request when the store is known to be dead.
+var errStoreUnavailable = errors.New("store is unavailable")
+
+type storeBreaker struct {
+	failures int
+	open     bool
+}
+
+// storeBreakers is a circuit breaker for each tikv store.
+// After threshold consecutive requests to a store fail to connect, the breaker of the store
+// opens and the following requests fail fast instead of waiting for the dial timeout, they
+// still back off like other failed requests. Read timeouts are not counted, a busy store
+// is not dead. A background goroutine probes the store every probeInterval and is the only
+// one that closes the breaker, once the store is reachable or after maxProbes failed probes.
+type storeBreakers struct {
+	sync.Mutex
+	m             map[string]*storeBreaker
+	threshold     int
+	probeInterval time.Duration
+	maxProbes     int // 0 means no limit.
+	probe         func(addr string) error
+	closeCh       chan struct{}
+	closed        bool
+}
+
+func newStoreBreakers(threshold int, probeInterval time.Duration, maxProbes int, probe func(addr string) error) *storeBreakers {
+	return &storeBreakers{
+		m:             make(map[string]*storeBreaker),
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		maxProbes:     maxProbes,
+		probe:         probe,
+		closeCh:       make(chan struct{}),
+	}
+}
+
+// allow returns errStoreUnavailable if the breaker of the store is open.
+func (b *storeBreakers) allow(addr string) error {
+	if b.threshold <= 0 {
+		return nil
+	}
+	b.Lock()
+	defer b.Unlock()
+	if sb, ok := b.m[addr]; ok && sb.open {
+		storeBreakerCounter.WithLabelValues("reject").Inc()
+		return errors.Annotatef(errStoreUnavailable, "addr %s", addr)
+	}
+	return nil
+}
+
+// onSuccess resets the consecutive failures of the store. An open breaker is left
+// to the probe goroutine, otherwise another probe goroutine may be started.
+func (b *storeBreakers) onSuccess(addr string) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.Lock()
+	if sb, ok := b.m[addr]; ok && !sb.open {
+		delete(b.m, addr)
+	}
+	b.Unlock()
+}
+
+// onFailure is called when connecting to the store fails.
+func (b *storeBreakers) onFailure(addr string) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	if b.closed {
+		return
+	}
+	sb, ok := b.m[addr]
+	if !ok {
+		sb = &storeBreaker{}
+		b.m[addr] = sb
+	}
+	if sb.open {
+		return
+	}
+	sb.failures++
+	if sb.failures < b.threshold {
+		return
+	}
+	sb.open = true
+	storeBreakerCounter.WithLabelValues("open").Inc()
+	log.Warnf("[tikv] %d consecutive requests to store %s failed, fail fast until it recovers", sb.failures, addr)
+	go b.runProbe(addr)
+}
+
+// runProbe probes the store until it is reachable, maxProbes probes have failed or the breakers
+// are closed. A store that never comes back, e.g. it is removed from the cluster, must not keep
+// its goroutine and breaker forever, so its breaker is dropped after maxProbes failed probes.
+func (b *storeBreakers) runProbe(addr string) {
+	ticker := time.NewTicker(b.probeInterval)
+	defer ticker.Stop()
+	for i := 1; ; i++ {
+		select {
+		case <-b.closeCh:
+			return
+		case <-ticker.C:
+		}
+		err := b.probe(addr)
+		if err == nil {
+			b.drop(addr)
+			storeBreakerCounter.WithLabelValues("close").Inc()
+			log.Infof("[tikv] store %s is reachable again", addr)
+			return
+		}
+		log.Debugf("[tikv] probe store %s failed: %v", addr, err)
+		if b.maxProbes > 0 && i >= b.maxProbes {
+			b.drop(addr)
+			storeBreakerCounter.WithLabelValues("expire").Inc()
+			log.Warnf("[tikv] store %s is still unreachable after %d probes, stop probing it", addr, i)
+			return
+		}
+	}
+}
+
+func (b *storeBreakers) drop(addr string) {
+	b.Lock()
+	delete(b.m, addr)
+	b.Unlock()
+}
+
+func (b *storeBreakers) close() {
+	b.Lock()
+	defer b.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.closeCh)
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikv
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	pb "github.com/pingcap/kvproto/pkg/kvrpcpb"
+)
+
+type testBreakerSuite struct{}
+
+var _ = Suite(&testBreakerSuite{})
+
+func (s *testBreakerSuite) TestStoreBreakers(c *C) {
+	var reachable int32
+	probe := func(addr string) error {
+		if atomic.LoadInt32(&reachable) == 0 {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+	b := newStoreBreakers(2, 10*time.Millisecond, 0, probe)
+	defer b.close()
+
+	b.onFailure("a")
+	c.Assert(b.allow("a"), IsNil)
+	// A success resets the consecutive failures.
+	b.onSuccess("a")
+	b.onFailure("a")
+	c.Assert(b.allow("a"), IsNil)
+	b.onFailure("a")
+	err := b.allow("a")
+	c.Assert(errors.Cause(err), Equals, errStoreUnavailable)
+	// Other stores are not affected.
+	c.Assert(b.allow("b"), IsNil)
+	// Only the probe closes an open breaker.
+	b.onSuccess("a")
+	c.Assert(b.allow("a"), NotNil)
+
+	// The breaker keeps open while the probe fails.
+	time.Sleep(50 * time.Millisecond)
+	c.Assert(b.allow("a"), NotNil)
+
+	atomic.StoreInt32(&reachable, 1)
+	for i := 0; i < 100 && b.allow("a") != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(b.allow("a"), IsNil)
+}
+
+func (s *testBreakerSuite) TestStoreBreakersMaxProbes(c *C) {
+	var probes int32
+	probe := func(addr string) error {
+		atomic.AddInt32(&probes, 1)
+		return errors.New("unreachable")
+	}
+	b := newStoreBreakers(1, 10*time.Millisecond, 3, probe)
+	defer b.close()
+
+	b.onFailure("a")
+	c.Assert(b.allow("a"), NotNil)
+	// The breaker is dropped after 3 failed probes.
+	for i := 0; i < 100 && b.allow("a") != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(b.allow("a"), IsNil)
+	b.Lock()
+	c.Assert(b.m, HasLen, 0)
+	b.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	c.Assert(atomic.LoadInt32(&probes), Equals, int32(3))
+
+	// It opens again if the store still fails.
+	b.onFailure("a")
+	c.Assert(b.allow("a"), NotNil)
+}
+
+func (s *testBreakerSuite) TestStoreBreakersDisabled(c *C) {
+	b := newStoreBreakers(0, time.Second, 0, probeStore)
+	defer b.close()
+	for i := 0; i < 10; i++ {
+		b.onFailure("a")
+	}
+	c.Assert(b.allow("a"), IsNil)
+}
+
+func (s *testBreakerSuite) TestClientFailFast(c *C) {
+	cli := newRPCClient()
+	defer cli.Close()
+	cli.breakers.close()
+	cli.breakers = newStoreBreakers(1, time.Hour, 0, probeStore)
+	req := &pb.Request{Type: pb.MessageType_CmdGet}
+	// Nothing listens on the port, so the first request fails on dialing.
+	_, err := cli.SendKVReq(":61238", req, ReadTimeoutShort)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err), Not(Equals), errStoreUnavailable)
+	_, err = cli.SendKVReq(":61238", req, ReadTimeoutShort)
+	c.Assert(errors.Cause(err), Equals, errStoreUnavailable)
+}
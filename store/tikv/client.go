@@ -37,36 +37,87 @@ type Client interface {
 	SendCopReq(addr string, req *coprocessor.Request, timeout time.Duration) (*coprocessor.Response, error)
 }
 
-const (
-	maxConnection     = 150
-	dialTimeout       = 5 * time.Second
-	writeTimeout      = 10 * time.Second
-	readTimeoutShort  = 20 * time.Second  // For requests that read/write several key-values.
-	readTimeoutMedium = 60 * time.Second  // For requests that may need scan region.
-	readTimeoutLong   = 150 * time.Second // For requests that may need scan region multiple times.
+const maxConnection = 150
+
+// Timeout classes and keepalive settings of the connections to tikv servers.
+// They should be set before the store is opened.
+var (
+	DialTimeout       = 5 * time.Second
+	WriteTimeout      = 10 * time.Second
+	ReadTimeoutShort  = 20 * time.Second  // For requests that read/write several key-values.
+	ReadTimeoutMedium = 60 * time.Second  // For requests that may need scan region.
+	ReadTimeoutLong   = 150 * time.Second // For requests that may need scan region multiple times.
+	// KeepAlivePeriod is the TCP keepalive period of the connections, 0 disables keepalive.
+	KeepAlivePeriod = 10 * time.Second
+)
+
+// Circuit breaker settings of the connections to tikv servers.
+// They should be set before the store is opened.
+var (
+	// StoreFailureThreshold is the number of consecutive requests failing to connect to a store
+	// after which requests to the store fail fast until it is reachable again, 0 disables it.
+	StoreFailureThreshold = 3
+	// StoreProbeInterval is the interval to probe a store whose requests fail fast.
+	StoreProbeInterval = 2 * time.Second
+	// StoreMaxProbes is the max number of failed probes to a store, after which its breaker is
+	// dropped, e.g. the store is removed from the cluster. If the store is still requested, the
+	// breaker opens again after StoreFailureThreshold failures.
+	StoreMaxProbes = 30
 )
 
 type rpcClient struct {
-	msgID uint64
-	p     *Pools
+	msgID    uint64
+	p        *Pools
+	breakers *storeBreakers
 }
 
 func newRPCClient() *rpcClient {
 	return &rpcClient{
 		msgID: 0,
 		p: NewPools(maxConnection, func(addr string) (*Conn, error) {
-			return NewConnection(addr, dialTimeout)
+			return newConnectionWithKeepAlive(addr)
 		}),
+		breakers: newStoreBreakers(StoreFailureThreshold, StoreProbeInterval, StoreMaxProbes, probeStore),
 	}
 }
 
+func newConnectionWithKeepAlive(addr string) (*Conn, error) {
+	conn, err := NewConnection(addr, DialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if KeepAlivePeriod > 0 {
+		err = conn.SetKeepAlive(KeepAlivePeriod)
+		if err != nil {
+			conn.Close()
+			return nil, errors.Trace(err)
+		}
+	}
+	return conn, nil
+}
+
+// probeStore checks if a store is reachable by dialing it.
+func probeStore(addr string) error {
+	conn, err := NewConnection(addr, DialTimeout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	conn.Close()
+	return nil
+}
+
 // SendCopReq sends a Request to co-processor and receives Response.
 func (c *rpcClient) SendCopReq(addr string, req *coprocessor.Request, timeout time.Duration) (*coprocessor.Response, error) {
 	start := time.Now()
 	defer func() { sendReqHistogram.WithLabelValues("cop").Observe(time.Since(start).Seconds()) }()
 
+	err := c.breakers.allow(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	conn, err := c.p.GetConn(addr)
 	if err != nil {
+		c.breakers.onFailure(addr)
 		return nil, errors.Trace(err)
 	}
 	defer c.p.PutConn(conn)
@@ -74,11 +125,12 @@ func (c *rpcClient) SendCopReq(addr string, req *coprocessor.Request, timeout ti
 		MsgType: msgpb.MessageType_CopReq,
 		CopReq:  req,
 	}
-	err = c.doSend(conn, &msg, writeTimeout, timeout)
+	err = c.doSend(conn, &msg, WriteTimeout, timeout)
 	if err != nil {
 		conn.Close()
 		return nil, errors.Trace(err)
 	}
+	c.breakers.onSuccess(addr)
 	if msg.GetMsgType() != msgpb.MessageType_CopResp || msg.GetCopResp() == nil {
 		conn.Close()
 		return nil, errors.Trace(errInvalidResponse)
@@ -91,8 +143,13 @@ func (c *rpcClient) SendKVReq(addr string, req *kvrpcpb.Request, timeout time.Du
 	start := time.Now()
 	defer func() { sendReqHistogram.WithLabelValues("kv").Observe(time.Since(start).Seconds()) }()
 
+	err := c.breakers.allow(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	conn, err := c.p.GetConn(addr)
 	if err != nil {
+		c.breakers.onFailure(addr)
 		return nil, errors.Trace(err)
 	}
 	defer c.p.PutConn(conn)
@@ -100,11 +157,12 @@ func (c *rpcClient) SendKVReq(addr string, req *kvrpcpb.Request, timeout time.Du
 		MsgType: msgpb.MessageType_KvReq,
 		KvReq:   req,
 	}
-	err = c.doSend(conn, &msg, writeTimeout, timeout)
+	err = c.doSend(conn, &msg, WriteTimeout, timeout)
 	if err != nil {
 		conn.Close()
 		return nil, errors.Trace(err)
 	}
+	c.breakers.onSuccess(addr)
 	if msg.GetMsgType() != msgpb.MessageType_KvResp || msg.GetKvResp() == nil {
 		conn.Close()
 		return nil, errors.Trace(errInvalidResponse)
@@ -134,6 +192,7 @@ func (c *rpcClient) doSend(conn *Conn, msg *msgpb.Message, writeTimeout time.Dur
 }
 
 func (c *rpcClient) Close() error {
+	c.breakers.close()
 	c.p.Close()
 	return nil
 }
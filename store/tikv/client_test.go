@@ -68,7 +68,7 @@ func (s *testClientSuite) TestSendBySelf(c *C) {
 	ver := uint64(0)
 	getReq.Version = ver
 	req.CmdGetReq = getReq
-	resp, err := cli.SendKVReq(":61234", req, readTimeoutShort)
+	resp, err := cli.SendKVReq(":61234", req, ReadTimeoutShort)
 	c.Assert(err, IsNil)
 	c.Assert(req.GetType(), Equals, resp.GetType())
 }
@@ -85,7 +85,7 @@ func (s *testClientSuite) TestRetryClose(c *C) {
 	defer l.Close()
 	cli := newRPCClient()
 	req := new(pb.Request)
-	resp, err := cli.SendKVReq(":61235", req, readTimeoutShort)
+	resp, err := cli.SendKVReq(":61235", req, ReadTimeoutShort)
 	c.Assert(err, NotNil)
 	c.Assert(resp, IsNil)
 }
@@ -107,7 +107,7 @@ func (s *testClientSuite) TestRetryReadThenClose(c *C) {
 	cli := newRPCClient()
 	req := new(pb.Request)
 	req.Type = pb.MessageType_CmdGet
-	resp, err := cli.SendKVReq(":61236", req, readTimeoutShort)
+	resp, err := cli.SendKVReq(":61236", req, ReadTimeoutShort)
 	c.Assert(err, NotNil)
 	c.Assert(resp, IsNil)
 }
@@ -138,9 +138,9 @@ func (s *testClientSuite) TestWrongMessageID(c *C) {
 		Type: pb.MessageType_CmdGet,
 	}
 	// Wrong ID for the first request, correct for the rests.
-	_, err := cli.SendKVReq(":61237", req, readTimeoutShort)
+	_, err := cli.SendKVReq(":61237", req, ReadTimeoutShort)
 	c.Assert(err, NotNil)
-	resp, err := cli.SendKVReq(":61237", req, readTimeoutShort)
+	resp, err := cli.SendKVReq(":61237", req, ReadTimeoutShort)
 	c.Assert(err, IsNil)
 	c.Assert(resp.GetType(), Equals, req.GetType())
 }
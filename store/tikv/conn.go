@@ -81,6 +81,18 @@ func (c *Conn) SetWriteDeadline(t time.Time) error {
 	return c.nc.SetWriteDeadline(t)
 }
 
+// SetKeepAlive enables TCP keepalive with the period on the net.Conn.
+func (c *Conn) SetKeepAlive(period time.Duration) error {
+	tc, ok := c.nc.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+	if err := tc.SetKeepAlive(true); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(tc.SetKeepAlivePeriod(period))
+}
+
 // Close closes the net.Conn.
 func (c *Conn) Close() {
 	if c.closed {
@@ -430,7 +430,7 @@ func (it *copIterator) handleTask(bo *Backoffer, task *copTask) (*coprocessor.Re
 			Data:   it.req.Data,
 			Ranges: task.ranges.toPBRanges(),
 		}
-		resp, err := sender.SendCopReq(req, task.region, readTimeoutMedium)
+		resp, err := sender.SendCopReq(req, task.region, ReadTimeoutMedium)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -269,7 +269,7 @@ func (w *GCWorker) resolveLocks(safePoint uint64) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
-		resp, err := w.store.SendKVReq(bo, req, loc.Region, readTimeoutMedium)
+		resp, err := w.store.SendKVReq(bo, req, loc.Region, ReadTimeoutMedium)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -343,7 +343,7 @@ func (w *GCWorker) DoGC(safePoint uint64) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
-		resp, err := w.store.SendKVReq(bo, req, loc.Region, readTimeoutLong)
+		resp, err := w.store.SendKVReq(bo, req, loc.Region, ReadTimeoutLong)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -193,7 +193,7 @@ func (s *testLockSuite) mustGetLock(c *C, key []byte) *Lock {
 	}
 	loc, err := s.store.regionCache.LocateKey(bo, key)
 	c.Assert(err, IsNil)
-	resp, err := s.store.SendKVReq(bo, req, loc.Region, readTimeoutShort)
+	resp, err := s.store.SendKVReq(bo, req, loc.Region, ReadTimeoutShort)
 	c.Assert(err, IsNil)
 	cmdGetResp := resp.GetCmdGetResp()
 	c.Assert(cmdGetResp, NotNil)
@@ -95,6 +95,14 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 18),
 		})
 
+	storeBreakerCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "tikvclient",
+			Name:      "store_breaker_total",
+			Help:      "Counter of store circuit breaker actions.",
+		}, []string{"type"})
+
 	gcWorkerCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -204,6 +212,7 @@ func init() {
 	prometheus.MustRegister(sendReqHistogram)
 	prometheus.MustRegister(coprocessorCounter)
 	prometheus.MustRegister(coprocessorHistogram)
+	prometheus.MustRegister(storeBreakerCounter)
 	prometheus.MustRegister(gcWorkerCounter)
 	prometheus.MustRegister(gcConfigGauge)
 	prometheus.MustRegister(gcHistogram)
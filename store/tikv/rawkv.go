@@ -135,7 +135,7 @@ func (c *RawKVClient) sendKVReq(key []byte, req *kvrpcpb.Request) (*kvrpcpb.Resp
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		resp, err := sender.SendKVReq(req, loc.Region, readTimeoutShort)
+		resp, err := sender.SendKVReq(req, loc.Region, ReadTimeoutShort)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -14,6 +14,7 @@
 package table
 
 import (
+	"github.com/juju/errors"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/util/types"
@@ -46,3 +47,35 @@ type Index interface {
 	// FetchValues fetched index column values in a row.
 	FetchValues(row []types.Datum) (columns []types.Datum, err error)
 }
+
+// IndexCreator creates the Index that encodes and maintains the index data of a table.
+type IndexCreator func(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) Index
+
+var indexCreators = make(map[model.IndexType]IndexCreator)
+
+// RegisterIndexType registers the IndexCreator for indices of the index type.
+// New index kinds register their creators on init, then tables create and maintain their
+// indices without knowing how the index data is encoded. Only the maintenance of the index
+// data is pluggable, the planner reads the index only if package plan has an access path for it.
+// It returns an error if the index type is already registered.
+func RegisterIndexType(tp model.IndexType, creator IndexCreator) error {
+	tp = tp.Normalize()
+	if _, ok := indexCreators[tp]; ok {
+		return errors.Errorf("index type %d is already registered", tp)
+	}
+	indexCreators[tp] = creator
+	return nil
+}
+
+// MustRegisterIndexType is like RegisterIndexType but panics if the index type is already registered.
+func MustRegisterIndexType(tp model.IndexType, creator IndexCreator) {
+	if err := RegisterIndexType(tp, creator); err != nil {
+		panic(err)
+	}
+}
+
+// GetIndexCreator returns the IndexCreator registered for the index type.
+func GetIndexCreator(tp model.IndexType) (IndexCreator, bool) {
+	creator, ok := indexCreators[tp.Normalize()]
+	return creator, ok
+}
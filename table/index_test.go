@@ -0,0 +1,57 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = Suite(&testIndexSuite{})
+
+type testIndexSuite struct{}
+
+type mockIndex struct {
+	Index
+}
+
+func (s *testIndexSuite) TestIndexTypeRegistry(c *C) {
+	defer testleak.AfterTest(c)()
+	// Registering index types changes the global registry, restore it after the test.
+	origin := indexCreators
+	indexCreators = make(map[model.IndexType]IndexCreator)
+	defer func() { indexCreators = origin }()
+
+	creator := func(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) Index {
+		return &mockIndex{}
+	}
+	_, ok := GetIndexCreator(model.IndexTypeBtree)
+	c.Assert(ok, IsFalse)
+	err := RegisterIndexType(model.IndexTypeBtree, creator)
+	c.Assert(err, IsNil)
+
+	// The index type is btree if it is not specified.
+	_, ok = GetIndexCreator(0)
+	c.Assert(ok, IsTrue)
+	err = RegisterIndexType(0, creator)
+	c.Assert(err, NotNil)
+	c.Assert(func() { MustRegisterIndexType(model.IndexTypeBtree, creator) }, PanicMatches, ".*already registered")
+
+	const mockIndexType model.IndexType = 100
+	MustRegisterIndexType(mockIndexType, creator)
+	got, ok := GetIndexCreator(mockIndexType)
+	c.Assert(ok, IsTrue)
+	c.Assert(got(nil, nil), FitsTypeOf, &mockIndex{})
+}
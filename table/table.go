@@ -53,6 +53,8 @@ var (
 	ErrIndexStateCantNone = terror.ClassTable.New(codeIndexStateCantNone, "index can not be in none state")
 	// ErrInvalidRecordKey returns for invalid record key.
 	ErrInvalidRecordKey = terror.ClassTable.New(codeInvalidRecordKey, "invalid record key")
+	// ErrUnsupportedIndexType returns for index type that is not registered.
+	ErrUnsupportedIndexType = terror.ClassTable.New(codeUnsupportedIndexType, "unsupported index type")
 )
 
 // RecordIterFunc is used for low-level record iteration.
@@ -136,6 +138,7 @@ const (
 	codeColumnStateNonPublic = 7
 	codeIndexStateCantNone   = 8
 	codeInvalidRecordKey     = 9
+	codeUnsupportedIndexType = 10
 
 	codeColumnCantNull  = 1048
 	codeUnknownColumn   = 1054
@@ -99,8 +99,75 @@ type index struct {
 	prefix  kv.Key
 }
 
-// NewIndex builds a new Index object.
-func NewIndex(tableInfo *model.TableInfo, indexInfo *model.IndexInfo) table.Index {
+func init() {
+	table.MustRegisterIndexType(model.IndexTypeBtree, newKVIndex)
+	table.MustRegisterIndexType(model.IndexTypeHash, newKVIndex)
+}
+
+// NewIndex builds a new Index object with the IndexCreator registered for its index type.
+// It returns ErrUnsupportedIndexType if the index type is not registered.
+func NewIndex(tableInfo *model.TableInfo, indexInfo *model.IndexInfo) (table.Index, error) {
+	creator, ok := table.GetIndexCreator(indexInfo.Tp)
+	if !ok {
+		return nil, table.ErrUnsupportedIndexType.Gen("index %s has unsupported type %d", indexInfo.Name, indexInfo.Tp)
+	}
+	return creator(tableInfo, indexInfo), nil
+}
+
+// unsupportedIndex is the index of a type without a registered IndexCreator, e.g. it is added
+// by a newer server. The table is still loaded so the other indices and the rows can be read,
+// but the index itself can't be read or written.
+type unsupportedIndex struct {
+	idxInfo *model.IndexInfo
+	err     error
+}
+
+// Meta implements table.Index Meta interface.
+func (c *unsupportedIndex) Meta() *model.IndexInfo {
+	return c.idxInfo
+}
+
+// Create implements table.Index Create interface.
+func (c *unsupportedIndex) Create(rm kv.RetrieverMutator, indexedValues []types.Datum, h int64) (int64, error) {
+	return 0, errors.Trace(c.err)
+}
+
+// Delete implements table.Index Delete interface.
+func (c *unsupportedIndex) Delete(m kv.Mutator, indexedValues []types.Datum, h int64) error {
+	return errors.Trace(c.err)
+}
+
+// Drop implements table.Index Drop interface.
+func (c *unsupportedIndex) Drop(rm kv.RetrieverMutator) error {
+	return errors.Trace(c.err)
+}
+
+// Exist implements table.Index Exist interface.
+func (c *unsupportedIndex) Exist(rm kv.RetrieverMutator, indexedValues []types.Datum, h int64) (bool, int64, error) {
+	return false, 0, errors.Trace(c.err)
+}
+
+// GenIndexKey implements table.Index GenIndexKey interface.
+func (c *unsupportedIndex) GenIndexKey(indexedValues []types.Datum, h int64) ([]byte, bool, error) {
+	return nil, false, errors.Trace(c.err)
+}
+
+// Seek implements table.Index Seek interface.
+func (c *unsupportedIndex) Seek(r kv.Retriever, indexedValues []types.Datum) (table.IndexIterator, bool, error) {
+	return nil, false, errors.Trace(c.err)
+}
+
+// SeekFirst implements table.Index SeekFirst interface.
+func (c *unsupportedIndex) SeekFirst(r kv.Retriever) (table.IndexIterator, error) {
+	return nil, errors.Trace(c.err)
+}
+
+// FetchValues implements table.Index FetchValues interface.
+func (c *unsupportedIndex) FetchValues(r []types.Datum) ([]types.Datum, error) {
+	return nil, errors.Trace(c.err)
+}
+
+func newKVIndex(tableInfo *model.TableInfo, indexInfo *model.IndexInfo) table.Index {
 	index := &index{
 		tblInfo: tableInfo,
 		idxInfo: indexInfo,
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/store/localstore"
 	"github.com/pingcap/tidb/store/localstore/goleveldb"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testleak"
@@ -61,7 +62,8 @@ func (s *testIndexSuite) TestIndex(c *C) {
 			},
 		},
 	}
-	index := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	index, err := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	c.Assert(err, IsNil)
 
 	// Test ununiq index.
 	txn, err := s.s.Begin()
@@ -145,7 +147,8 @@ func (s *testIndexSuite) TestIndex(c *C) {
 			},
 		},
 	}
-	index = tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	index, err = tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	c.Assert(err, IsNil)
 
 	// Test uniq index.
 	txn, err = s.s.Begin()
@@ -189,7 +192,8 @@ func (s *testIndexSuite) TestCombineIndexSeek(c *C) {
 			},
 		},
 	}
-	index := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	index, err := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	c.Assert(err, IsNil)
 
 	txn, err := s.s.Begin()
 	c.Assert(err, IsNil)
@@ -198,7 +202,8 @@ func (s *testIndexSuite) TestCombineIndexSeek(c *C) {
 	_, err = index.Create(txn, values, 1)
 	c.Assert(err, IsNil)
 
-	index2 := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	index2, err := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	c.Assert(err, IsNil)
 	iter, hit, err := index2.Seek(txn, types.MakeDatums("abc", nil))
 	c.Assert(err, IsNil)
 	defer iter.Close()
@@ -207,3 +212,38 @@ func (s *testIndexSuite) TestCombineIndexSeek(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(h, Equals, int64(1))
 }
+
+func (s *testIndexSuite) TestUnsupportedIndexType(c *C) {
+	defer testleak.AfterTest(c)()
+	tblInfo := &model.TableInfo{
+		ID:    1,
+		Name:  model.NewCIStr("t"),
+		State: model.StatePublic,
+		Indices: []*model.IndexInfo{
+			{
+				ID:    2,
+				Name:  model.NewCIStr("idx"),
+				State: model.StatePublic,
+				Tp:    model.IndexType(100),
+			},
+		},
+	}
+	_, err := tables.NewIndex(tblInfo, tblInfo.Indices[0])
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+	// The table is still created, but the index can't be used.
+	tb, err := tables.TableFromMeta(nil, tblInfo)
+	c.Assert(err, IsNil)
+	c.Assert(tb.Indices(), HasLen, 1)
+	idx := tb.Indices()[0]
+	c.Assert(idx.Meta(), Equals, tblInfo.Indices[0])
+	_, err = idx.Create(nil, types.MakeDatums(1), 1)
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+	_, err = idx.SeekFirst(nil)
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+
+	// The index type is btree if it is not specified.
+	tblInfo.Indices[0].Tp = 0
+	tb, err = tables.TableFromMeta(nil, tblInfo)
+	c.Assert(err, IsNil)
+	c.Assert(tb.Indices(), HasLen, 1)
+}
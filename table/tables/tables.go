@@ -51,6 +51,9 @@ type Table struct {
 	indexPrefix     kv.Key
 	alloc           autoid.Allocator
 	meta            *model.TableInfo
+	// errUnsupportedIndex is set if the table has an index of unsupported type, the rows can't
+	// be written as the index can't be maintained.
+	errUnsupportedIndex error
 }
 
 // MockTableFromMeta only serves for test.
@@ -80,8 +83,14 @@ func TableFromMeta(alloc autoid.Allocator, tblInfo *model.TableInfo) (table.Tabl
 		if idxInfo.State == model.StateNone {
 			return nil, table.ErrIndexStateCantNone.Gen("index %s can't be in none state", idxInfo.Name)
 		}
-
-		idx := NewIndex(tblInfo, idxInfo)
+		idx, err := NewIndex(tblInfo, idxInfo)
+		if terror.ErrorEqual(err, table.ErrUnsupportedIndexType) {
+			// Loading the schema must not fail because of one table, only the writes to it fail.
+			idx = &unsupportedIndex{idxInfo: idxInfo, err: err}
+			t.errUnsupportedIndex = err
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
 		t.indices = append(t.indices, idx)
 	}
 
@@ -170,6 +179,9 @@ func (t *Table) FirstKey() kv.Key {
 
 // UpdateRecord implements table.Table UpdateRecord interface.
 func (t *Table) UpdateRecord(ctx context.Context, h int64, oldData []types.Datum, newData []types.Datum, touched map[int]bool) error {
+	if t.errUnsupportedIndex != nil {
+		return errors.Trace(t.errUnsupportedIndex)
+	}
 	// We should check whether this table has on update column which state is write only.
 	currentData := make([]types.Datum, len(t.WritableCols()))
 	copy(currentData, newData)
@@ -284,6 +296,9 @@ func (t *Table) rebuildIndices(rm kv.RetrieverMutator, h int64, touched map[int]
 
 // AddRecord implements table.Table AddRecord interface.
 func (t *Table) AddRecord(ctx context.Context, r []types.Datum) (recordID int64, err error) {
+	if t.errUnsupportedIndex != nil {
+		return 0, errors.Trace(t.errUnsupportedIndex)
+	}
 	var hasRecordID bool
 	for _, col := range t.Cols() {
 		if col.IsPKHandleColumn(t.meta) {
@@ -481,6 +496,9 @@ func (t *Table) Row(ctx context.Context, h int64) ([]types.Datum, error) {
 
 // RemoveRecord implements table.Table RemoveRecord interface.
 func (t *Table) RemoveRecord(ctx context.Context, h int64, r []types.Datum) error {
+	if t.errUnsupportedIndex != nil {
+		return errors.Trace(t.errUnsupportedIndex)
+	}
 	err := t.removeRowData(ctx, h)
 	if err != nil {
 		return errors.Trace(err)
@@ -16,10 +16,12 @@ package tables_test
 import (
 	"testing"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/localstore"
@@ -27,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/types"
@@ -284,3 +287,51 @@ func (ts *testSuite) TestIterRecords(c *C) {
 	c.Assert(totalCount, Equals, 2)
 	c.Assert(ctx.Txn().Commit(), IsNil)
 }
+
+func (ts *testSuite) TestUnsupportedIndexType(c *C) {
+	_, err := ts.se.Execute("CREATE TABLE test.t_unsupported (a int primary key, b int, index idx(b))")
+	c.Assert(err, IsNil)
+	_, err = ts.se.Execute("INSERT INTO test.t_unsupported VALUES (1, 1)")
+	c.Assert(err, IsNil)
+	ctx := ts.se.(context.Context)
+	dom := sessionctx.GetDomain(ctx)
+	tb, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t_unsupported"))
+	c.Assert(err, IsNil)
+	db, ok := dom.InfoSchema().SchemaByName(model.NewCIStr("test"))
+	c.Assert(ok, IsTrue)
+
+	// Change the index to a type this server doesn't support, e.g. it is added by a newer server,
+	// then fully load the InfoSchema.
+	tblInfo := tb.Meta().Clone()
+	tblInfo.Indices[0].Tp = model.IndexType(100)
+	err = kv.RunInNewTxn(ts.store, false, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		if err1 := m.UpdateTable(db.ID, tblInfo); err1 != nil {
+			return errors.Trace(err1)
+		}
+		_, err1 := m.GenSchemaVersion()
+		return errors.Trace(err1)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(dom.Reload(), IsNil)
+
+	tb, err = dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t_unsupported"))
+	c.Assert(err, IsNil)
+	c.Assert(tb.Indices(), HasLen, 1)
+	// The rows are read without the index, but can't be written.
+	rs, err := ts.se.Execute("SELECT a FROM test.t_unsupported USE INDEX (idx) WHERE b = 1")
+	c.Assert(err, IsNil)
+	rows, err := tidb.GetRows(rs[0])
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 1)
+	_, err = ts.se.Execute("INSERT INTO test.t_unsupported VALUES (2, 2)")
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+	_, err = ts.se.Execute("UPDATE test.t_unsupported SET b = 2")
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+	_, err = ts.se.Execute("DELETE FROM test.t_unsupported")
+	c.Assert(terror.ErrorEqual(err, table.ErrUnsupportedIndexType), IsTrue)
+
+	// The table can still be dropped.
+	_, err = ts.se.Execute("DROP TABLE test.t_unsupported")
+	c.Assert(err, IsNil)
+}
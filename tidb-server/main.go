@@ -59,6 +59,14 @@ var (
 	metricsAddr     = flag.String("metrics-addr", "", "prometheus pushgateway address, leaves it empty will disable prometheus push.")
 	metricsInterval = flag.Int("metrics-interval", 15, "prometheus client push interval in second, set \"0\" to disable prometheus push.")
 	binlogSocket    = flag.String("binlog-socket", "", "socket file to write binlog")
+
+	tikvReadTimeoutShort  = flag.Duration("tikv-read-timeout-short", tikv.ReadTimeoutShort, "timeout of tikv requests that read/write several key-values.")
+	tikvReadTimeoutMedium = flag.Duration("tikv-read-timeout-medium", tikv.ReadTimeoutMedium, "timeout of tikv requests that may need scan region.")
+	tikvReadTimeoutLong   = flag.Duration("tikv-read-timeout-long", tikv.ReadTimeoutLong, "timeout of tikv requests that may need scan region multiple times.")
+	tikvKeepAlive         = flag.Duration("tikv-keepalive", tikv.KeepAlivePeriod, "keepalive period of connections to tikv, set \"0\" to disable keepalive.")
+	tikvFailureThreshold  = flag.Int("tikv-failure-threshold", tikv.StoreFailureThreshold, "consecutive requests failing to connect to a tikv store before its requests fail fast, set \"0\" to disable it.")
+	tikvProbeInterval     = flag.Duration("tikv-probe-interval", tikv.StoreProbeInterval, "interval to probe a tikv store whose requests fail fast.")
+	tikvMaxProbes         = flag.Int("tikv-max-probes", tikv.StoreMaxProbes, "failed probes to a tikv store before it is no longer probed.")
 )
 
 func main() {
@@ -98,6 +106,7 @@ func main() {
 		plan.JoinConcurrency = *joinCon
 	}
 	plan.AllowCartesianProduct = *crossJoin
+	setTiKVClientConfig()
 	// Call this before setting log level to make sure that TiDB info could be printed.
 	printer.PrintTiDBInfo()
 	log.SetLevelByString(cfg.LogLevel)
@@ -199,6 +208,26 @@ func prometheusPushClient(addr string, interval time.Duration) {
 	}
 }
 
+// setTiKVClientConfig sets the timeouts, keepalive and circuit breaker of the tikv client from flags.
+func setTiKVClientConfig() {
+	if *tikvReadTimeoutShort <= 0 || *tikvReadTimeoutMedium <= 0 || *tikvReadTimeoutLong <= 0 {
+		log.Fatalf("invalid tikv read timeout %v/%v/%v", *tikvReadTimeoutShort, *tikvReadTimeoutMedium, *tikvReadTimeoutLong)
+	}
+	if *tikvProbeInterval <= 0 {
+		log.Fatalf("invalid tikv probe interval %v", *tikvProbeInterval)
+	}
+	if *tikvMaxProbes <= 0 {
+		log.Fatalf("invalid tikv max probes %d", *tikvMaxProbes)
+	}
+	tikv.ReadTimeoutShort = *tikvReadTimeoutShort
+	tikv.ReadTimeoutMedium = *tikvReadTimeoutMedium
+	tikv.ReadTimeoutLong = *tikvReadTimeoutLong
+	tikv.KeepAlivePeriod = *tikvKeepAlive
+	tikv.StoreFailureThreshold = *tikvFailureThreshold
+	tikv.StoreProbeInterval = *tikvProbeInterval
+	tikv.StoreMaxProbes = *tikvMaxProbes
+}
+
 // parseLease parses lease argument string.
 func parseLease() time.Duration {
 	dur, err := time.ParseDuration(*lease)